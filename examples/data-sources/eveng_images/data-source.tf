@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    eveng = {
+      source = "CorentinPtrl/eveng"
+    }
+  }
+}
+
+provider "eveng" {}
+
+data "eveng_images" "vios" {
+  template   = "vios"
+  name_regex = "^vios-adventerprisek9"
+}
+
+output "latest_vios_image" {
+  value = data.eveng_images.vios.latest
+}
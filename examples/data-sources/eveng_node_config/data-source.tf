@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    eveng = {
+      source = "CorentinPtrl/eveng"
+    }
+  }
+}
+
+provider "eveng" {}
+
+data "eveng_node_config" "lab" {
+  lab_path = "/labs/core.unl"
+}
+
+resource "local_file" "config" {
+  for_each = { for node in data.eveng_node_config.lab.nodes : node.name => node.config }
+
+  filename = "${path.module}/configs/${each.key}.txt"
+  content  = each.value
+}
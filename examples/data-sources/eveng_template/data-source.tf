@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    eveng = {
+      source = "CorentinPtrl/eveng"
+    }
+  }
+}
+
+provider "eveng" {}
+
+data "eveng_template" "example" {
+  name = "vios"
+}
+
+output "vios_images" {
+  value = data.eveng_template.example.images
+}
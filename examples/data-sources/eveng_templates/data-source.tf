@@ -0,0 +1,15 @@
+terraform {
+  required_providers {
+    eveng = {
+      source = "CorentinPtrl/eveng"
+    }
+  }
+}
+
+provider "eveng" {}
+
+data "eveng_templates" "example" {}
+
+output "installed_templates" {
+  value = [for t in data.eveng_templates.example.templates : t.name if t.images_installed]
+}
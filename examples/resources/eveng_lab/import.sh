@@ -0,0 +1,2 @@
+# Labs can be imported using their full path.
+terraform import eveng_lab.example /team/a/core.unl
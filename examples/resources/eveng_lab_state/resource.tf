@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    eveng = {
+      source = "CorentinPtrl/eveng"
+    }
+  }
+}
+
+provider "eveng" {}
+
+resource "eveng_lab" "example" {
+  name = "LabStateExample"
+}
+
+resource "eveng_node" "node" {
+  lab_path = eveng_lab.example.path
+  name     = "switch"
+  template = "viosl2"
+  type     = "qemu"
+}
+
+resource "eveng_lab_state" "example" {
+  lab_path      = eveng_lab.example.path
+  desired_state = "running"
+
+  depends_on = [eveng_node.node]
+}
@@ -0,0 +1,2 @@
+# Nodes can be imported using the lab path and the node ID, separated by a colon.
+terraform import eveng_node.node /labs/core.unl:3
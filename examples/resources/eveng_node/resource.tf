@@ -16,7 +16,7 @@ resource "eveng_lab" "example" {
 
 resource "eveng_node" "node" {
   lab_path = eveng_lab.example.path
-  name     = "vpc"
-  template = "vpcs"
+  name     = "switch"
+  template = "viosl2"
   type     = "qemu"
 }
\ No newline at end of file
@@ -0,0 +1,5 @@
+# Links can be imported using the lab path and the network ID, separated by a colon.
+terraform import eveng_node_link.node /labs/core.unl:4
+
+# When several interfaces share a network, append the source node ID and port.
+terraform import eveng_node_link.node /labs/core.unl:4:1:Gi0/1
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"sync"
+	"time"
+)
+
+// labCacheTTL is how long a cached response stays valid when the lab is not written to.
+// It is meant to cover a single refresh, during which many resources read the same lab.
+const labCacheTTL = 30 * time.Second
+
+// labCache is a short-lived read-through cache of API responses, grouped by lab so that
+// every response of a lab can be invalidated when the lab is written to. Concurrent reads
+// of the same key share a single API call.
+type labCache struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	labs map[string]map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	value   any
+	err     error
+	expires time.Time
+}
+
+func newLabCache(ttl time.Duration) *labCache {
+	return &labCache{ttl: ttl, labs: make(map[string]map[string]*cacheEntry)}
+}
+
+// cached returns the cached response for key in the lab, or calls fetch and caches its result.
+// Errors are not cached. The returned value is shared and must not be modified.
+func cached[T any](c *labCache, labPath string, key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entries, ok := c.labs[labPath]
+	if !ok {
+		entries = make(map[string]*cacheEntry)
+		c.labs[labPath] = entries
+	}
+	entry, ok := entries[key]
+	if ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		c.mu.Unlock()
+		<-entry.done
+		if entry.err == nil {
+			return entry.value.(T), nil
+		}
+		// The call shared with this one failed, make our own.
+		return fetch()
+	}
+	entry = &cacheEntry{done: make(chan struct{})}
+	entries[key] = entry
+	c.mu.Unlock()
+
+	value, err := fetch()
+
+	c.mu.Lock()
+	entry.value, entry.err = value, err
+	entry.expires = time.Now().Add(c.ttl)
+	if err != nil && entries[key] == entry {
+		delete(entries, key)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return value, err
+}
+
+// invalidate drops every cached response of the lab.
+func (c *labCache) invalidate(labPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.labs, labPath)
+}
+
+// invalidateAll drops every cached response, for writes that are not bound to a single lab.
+func (c *labCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.labs = make(map[string]map[string]*cacheEntry)
+}
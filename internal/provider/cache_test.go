@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLabCacheExpiry(t *testing.T) {
+	cache := newLabCache(50 * time.Millisecond)
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := cached(cache, "/lab.unl", "topology", fetch)
+		if err != nil || value != 1 {
+			t.Fatalf("expected the cached value 1, got %d and %v", value, err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	value, err := cached(cache, "/lab.unl", "topology", fetch)
+	if err != nil || value != 2 {
+		t.Fatalf("expected an expired entry to be fetched again, got %d and %v", value, err)
+	}
+
+	cache.invalidate("/lab.unl")
+	value, _ = cached(cache, "/lab.unl", "topology", fetch)
+	if value != 3 {
+		t.Fatalf("expected an invalidated entry to be fetched again, got %d", value)
+	}
+	value, _ = cached(cache, "/other.unl", "topology", fetch)
+	if value != 4 {
+		t.Fatalf("expected entries to be separate per lab, got %d", value)
+	}
+	cache.invalidateAll()
+	value, _ = cached(cache, "/other.unl", "topology", fetch)
+	if value != 5 {
+		t.Fatalf("expected every entry to be dropped, got %d", value)
+	}
+}
+
+func TestLabCacheErrorsNotCached(t *testing.T) {
+	cache := newLabCache(time.Minute)
+	_, err := cached(cache, "/lab.unl", "topology", func() (int, error) {
+		return 0, errors.New("unavailable")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	value, err := cached(cache, "/lab.unl", "topology", func() (int, error) {
+		return 1, nil
+	})
+	if err != nil || value != 1 {
+		t.Fatalf("expected a failed call to be made again, got %d and %v", value, err)
+	}
+}
+
+func TestLabCacheSingleFlight(t *testing.T) {
+	cache := newLabCache(time.Minute)
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func() (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = cached(cache, "/lab.unl", "topology", fetch)
+	}()
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = cached(cache, "/lab.unl", "topology", fetch)
+		}()
+	}
+	// Let the other readers reach the in-flight entry before the call completes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected concurrent reads to share a single call, got %d calls", calls.Load())
+	}
+	for i, result := range results {
+		if result != 42 {
+			t.Errorf("reader %d: expected 42, got %d", i, result)
+		}
+	}
+}
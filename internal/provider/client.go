@@ -0,0 +1,787 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// sessionExpiredMessages are the error messages returned by EVE-NG when the session cookie
+// is no longer valid (HTTP 401 and 412 responses).
+var sessionExpiredMessages = []string{
+	"session timed out",
+	"not authenticated",
+	"unauthorized",
+	"(90001)",
+}
+
+// RetryPolicy describes how calls failing with a transient error are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// WaitMin and WaitMax bound the exponential backoff between two attempts.
+	WaitMin time.Duration
+	WaitMax time.Duration
+	// RetryableStatuses are the HTTP status codes considered transient.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns the retry policy used when the provider configuration sets none.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:        3,
+		WaitMin:           time.Second,
+		WaitMax:           30 * time.Second,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+// backoff returns the wait before the retry following the given attempt, starting at 0.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.WaitMin
+	for i := 0; i < attempt && wait < p.WaitMax; i++ {
+		wait *= 2
+	}
+	if wait > p.WaitMax {
+		wait = p.WaitMax
+	}
+	return wait
+}
+
+// isRetryable reports whether err is a transient failure. A request that could not be sent
+// because the connection to the server failed is always retried. Other failures are only
+// retried for idempotent requests, as a request creating an object may have been applied
+// before the connection was lost: a connection error, or a response with one of the retryable
+// HTTP statuses.
+func (p RetryPolicy) isRetryable(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || tlsErrorDetail("", err) != "" {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(p.RetryableStatuses, apiErr.StatusCode)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// Client sends the requests to the EVE-NG API shared by every resource and data source.
+// evengsdk sends its requests through an HTTP transport that skips the verification of the TLS
+// certificate and cannot be replaced, so the client sends them itself and only reuses the types
+// of the SDK. This also leaves out the retries of the SDK, which retried every request on any
+// error, so the retry policy is the only one applied.
+// Calls failing with a transient error are retried according to the retry policy, and
+// when a call fails because the session expired, it logs in again and retries the call once.
+type Client struct {
+	username string
+	password string
+	host     string
+	baseURL  *url.URL
+	http     *http.Client
+	retry    RetryPolicy
+	labs     *labLocks
+	cache    *labCache
+	// sessions is the file sessions are kept in across provider runs, nil when disabled.
+	sessions *sessionCacheFile
+
+	// send holds requests one at a time, EVE-NG keeping the lab opened by the user in the session.
+	send sync.Mutex
+
+	mu      sync.RWMutex
+	session *session
+
+	Lab     *labService
+	Node    *nodeService
+	Network *networkService
+	Folder  *folderService
+}
+
+// session is an authenticated EVE-NG session.
+type session struct {
+	cookie *http.Cookie
+	pro    bool
+}
+
+// sessionCookie is the name of the cookie EVE-NG identifies sessions with.
+const sessionCookie = "unetlab_session"
+
+// apiError is an error response of the EVE-NG API.
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+// NewClient logs in to the EVE-NG API and returns a new Client. The certificate of the server
+// is verified against tlsConfig.
+// labWriteConcurrency is the number of resources allowed to modify the same lab at once.
+// When sessionCachePath is not empty, the session stored in it by a previous run is reused
+// while the server still accepts it, and new sessions are stored in it.
+func NewClient(ctx context.Context, username, password, host string, tlsConfig *tls.Config, retry RetryPolicy, labWriteConcurrency int, sessionCachePath string) (*Client, error) {
+	if !strings.HasSuffix(host, "/") {
+		host += "/"
+	}
+	baseURL, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	c := &Client{
+		username: username,
+		password: password,
+		host:     host,
+		baseURL:  baseURL,
+		http:     &http.Client{Transport: transport},
+		retry:    retry,
+		labs:     newLabLocks(labWriteConcurrency),
+		cache:    newLabCache(labCacheTTL),
+	}
+	if sessionCachePath != "" {
+		c.sessions = &sessionCacheFile{path: sessionCachePath}
+	}
+	c.session = c.cachedSession(ctx)
+	if c.session == nil {
+		session, err := withRetry(ctx, c, "login", true, func() (*session, error) {
+			return c.login(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.session = session
+		c.saveSession(ctx, session)
+	}
+	c.Lab = &labService{client: c}
+	c.Node = &nodeService{client: c}
+	c.Network = &networkService{client: c}
+	c.Folder = &folderService{client: c}
+	return c, nil
+}
+
+// login opens a new session.
+func (c *Client) login(ctx context.Context) (*session, error) {
+	_, cookies, err := c.roundTrip(ctx, nil, http.MethodPost, "api/auth/login", evengsdk.Login{
+		Username: c.username,
+		Password: c.password,
+		Html5:    "0",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	s := &session{}
+	for _, cookie := range cookies {
+		if cookie.Name == sessionCookie {
+			s.cookie = cookie
+		}
+	}
+	if s.cookie == nil {
+		return nil, errors.New("login failed: the server did not return a session cookie")
+	}
+	var status struct {
+		Version string `json:"version"`
+	}
+	if err := c.decode(ctx, s, http.MethodGet, "api/status", nil, &status); err == nil {
+		s.pro = strings.Contains(strings.ToLower(status.Version), "pro")
+	}
+	return s, nil
+}
+
+// cachedSession returns the session stored in the session cache file, or nil when there is
+// none or the server no longer accepts it.
+func (c *Client) cachedSession(ctx context.Context) *session {
+	if c.sessions == nil {
+		return nil
+	}
+	s, err := c.sessions.load(c.username, c.host)
+	if err != nil {
+		tflog.Warn(ctx, "Failed to read the EVE-NG session cache file", map[string]interface{}{
+			"path":  c.sessions.path,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if s == nil {
+		return nil
+	}
+	if err := c.decode(ctx, s, http.MethodGet, "api/auth", nil, nil); err != nil {
+		tflog.Debug(ctx, "Cached EVE-NG session rejected, logging in", map[string]interface{}{
+			"host":  c.host,
+			"error": err.Error(),
+		})
+		return nil
+	}
+	tflog.Debug(ctx, "Reusing cached EVE-NG session", map[string]interface{}{
+		"host":     c.host,
+		"username": c.username,
+	})
+	return s
+}
+
+// saveSession stores s in the session cache file, when enabled. Failures only cost a login
+// on the next run, so they are logged and ignored.
+func (c *Client) saveSession(ctx context.Context, s *session) {
+	if c.sessions == nil {
+		return
+	}
+	if err := c.sessions.save(c.username, c.host, s); err != nil {
+		tflog.Warn(ctx, "Failed to write the EVE-NG session cache file", map[string]interface{}{
+			"path":  c.sessions.path,
+			"error": err.Error(),
+		})
+	}
+}
+
+// currentSession returns the current session.
+func (c *Client) currentSession() *session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.session
+}
+
+// relogin replaces the session by a new one, unless another call already did so since expired
+// was used.
+func (c *Client) relogin(ctx context.Context, expired *session) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session != expired {
+		return nil
+	}
+	tflog.Info(ctx, "EVE-NG session expired, logging in again", map[string]interface{}{
+		"host":     c.host,
+		"username": c.username,
+	})
+	s, err := c.login(ctx)
+	if err != nil {
+		return err
+	}
+	c.session = s
+	c.saveSession(ctx, s)
+	return nil
+}
+
+// roundTrip sends a single request with the cookie of the session s, when not nil, and returns
+// the response of the API and its cookies. Responses other than a JSON document with a success
+// status are returned as an *apiError.
+func (c *Client) roundTrip(ctx context.Context, s *session, method, path string, body interface{}) (*evengsdk.Response, []*http.Cookie, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Like evengsdk, do not reuse connections, the server dropping idle ones without notice.
+	req.Close = true
+	req.Header.Set("Content-Type", "application/json")
+	if s != nil {
+		req.AddCookie(s.cookie)
+	}
+
+	c.send.Lock()
+	resp, err := c.http.Do(req)
+	c.send.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	var response evengsdk.Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, nil, &apiError{StatusCode: resp.StatusCode, Message: resp.Status}
+	}
+	code, _ := response.Code.Int64()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || response.Status != "success" || (response.Code != "" && (code < 200 || code > 300)) {
+		message := response.Message
+		if message == "" {
+			message = resp.Status
+		}
+		return nil, nil, &apiError{StatusCode: resp.StatusCode, Message: message}
+	}
+	return &response, resp.Cookies(), nil
+}
+
+// decode sends a single request and decodes the data of the response into out, when not nil.
+func (c *Client) decode(ctx context.Context, s *session, method, path string, body interface{}, out interface{}) error {
+	response, _, err := c.roundTrip(ctx, s, method, path, body)
+	if err != nil || out == nil {
+		return err
+	}
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// LockLab blocks until the caller may modify the lab, or until ctx is done. Every write to
+// a lab goes through the .unl file of the lab, so resources modifying the same lab must hold
+// the lock for the whole operation while reads stay parallel. The returned function releases
+// the lock.
+func (c *Client) LockLab(ctx context.Context, labPath string) (func(), error) {
+	return c.labs.acquire(ctx, labPath)
+}
+
+// IsPro reports whether the server runs the Pro version of EVE-NG.
+func (c *Client) IsPro() bool {
+	return c.currentSession().pro
+}
+
+// GetAuth returns the user and the lab bound to the session.
+func (c *Client) GetAuth(ctx context.Context) (*evengsdk.Auth, error) {
+	return get[*evengsdk.Auth](ctx, c, "api/auth")
+}
+
+// do sends a request to the API and decodes the data of the response into out, when not nil.
+// The request is retried on transient errors, POST requests being considered not idempotent.
+// When the session has expired, it logs in again and sends the request once more.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	return c.request(ctx, method, path, body, out, method != http.MethodPost)
+}
+
+// query is the variant of do for POST requests that only read data, retried like GET requests.
+func (c *Client) query(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return c.request(ctx, http.MethodPost, path, body, out, true)
+}
+
+func (c *Client) request(ctx context.Context, method, path string, body interface{}, out interface{}, idempotent bool) error {
+	_, err := withRetry(ctx, c, method+" "+path, idempotent, func() (struct{}, error) {
+		s := c.currentSession()
+		err := c.decode(ctx, s, method, path, body, out)
+		if err == nil || !isSessionExpired(err) {
+			return struct{}{}, err
+		}
+		if loginErr := c.relogin(ctx, s); loginErr != nil {
+			return struct{}{}, fmt.Errorf("%w (failed to log in again: %s)", err, loginErr)
+		}
+		return struct{}{}, c.decode(ctx, c.currentSession(), method, path, body, out)
+	})
+	return err
+}
+
+// get sends a GET request and returns the data of the response.
+func get[T any](ctx context.Context, c *Client, path string) (T, error) {
+	var result T
+	err := c.do(ctx, http.MethodGet, path, nil, &result)
+	return result, err
+}
+
+// write is the variant of do for requests modifying the lab at labPath. The cached responses
+// of the lab are dropped, even when the request fails as it may have been applied.
+func (c *Client) write(ctx context.Context, labPath string, method, path string, body interface{}, out interface{}) error {
+	defer c.cache.invalidate(labPath)
+	return c.do(ctx, method, path, body, out)
+}
+
+// withRetry runs fn until it succeeds, fails with an error that is not transient or exhausts
+// the retries of the policy. The wait between two attempts is interrupted when ctx is done.
+func withRetry[T any](ctx context.Context, c *Client, operation string, idempotent bool, fn func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := fn()
+		if err == nil || attempt >= c.retry.MaxRetries || !c.retry.isRetryable(err, idempotent) {
+			return result, err
+		}
+		wait := c.retry.backoff(attempt)
+		tflog.Warn(ctx, fmt.Sprintf("EVE-NG API %s failed, retrying: %s", operation, err), map[string]interface{}{
+			"host":        c.host,
+			"attempt":     attempt + 1,
+			"max_retries": c.retry.MaxRetries,
+			"wait":        wait.String(),
+		})
+		if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+			return result, fmt.Errorf("%w (retry aborted: %s)", err, sleepErr)
+		}
+	}
+}
+
+// isSessionExpired reports whether err was caused by an expired EVE-NG session.
+func isSessionExpired(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusPreconditionFailed) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, expired := range sessionExpiredMessages {
+		if strings.Contains(msg, expired) {
+			return true
+		}
+	}
+	return false
+}
+
+// labLocks is a keyed semaphore limiting the number of concurrent writers of each lab.
+type labLocks struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+func newLabLocks(limit int) *labLocks {
+	if limit < 1 {
+		limit = 1
+	}
+	return &labLocks{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+func (l *labLocks) acquire(ctx context.Context, labPath string) (func(), error) {
+	l.mu.Lock()
+	slots, ok := l.slots[labPath]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[labPath] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	default:
+		tflog.Debug(ctx, "Waiting for another operation on the lab", map[string]interface{}{
+			"lab_path": labPath,
+		})
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for another operation on lab %s: %w", labPath, ctx.Err())
+		}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-slots })
+	}, nil
+}
+
+// splitLabURL splits the path of a lab into its folder, ending with a slash, and its escaped
+// file name, as the URLs of the API expect them.
+func splitLabURL(path string) (string, string) {
+	i := strings.LastIndex(path, "/") + 1
+	return path[:i], url.QueryEscape(path[i:])
+}
+
+// labURL returns the URL of the lab at path.
+func labURL(path string) string {
+	dir, name := splitLabURL(path)
+	return "api/labs" + dir + name
+}
+
+// labFileName returns the name of the lab created or updated at path: the name of its .unl
+// file, or name when path does not end with one.
+func labFileName(path string, name string) string {
+	file := path[strings.LastIndex(path, "/")+1:]
+	if strings.Contains(file, ".unl") {
+		return file[:strings.LastIndex(file, ".")]
+	}
+	return name
+}
+
+type labService struct {
+	client *Client
+}
+
+func (s *labService) GetLab(ctx context.Context, path string) (*evengsdk.Lab, error) {
+	return get[*evengsdk.Lab](ctx, s.client, labURL(path))
+}
+
+// IsLocked reports whether the lab is locked. The API returns the lock either as a boolean or
+// as a number.
+func (s *labService) IsLocked(ctx context.Context, path string) (bool, error) {
+	lab, err := get[struct {
+		Lock interface{} `json:"lock"`
+	}](ctx, s.client, labURL(path))
+	if err != nil {
+		return false, err
+	}
+	switch lock := lab.Lock.(type) {
+	case bool:
+		return lock, nil
+	case float64:
+		return lock != 0, nil
+	case string:
+		return lock == "1" || lock == "true", nil
+	}
+	return false, nil
+}
+
+func (s *labService) CreateLab(ctx context.Context, path string, lab evengsdk.Lab) error {
+	lab.Name = labFileName(path, lab.Name)
+	lab.Path, _ = splitLabURL(path)
+	return s.client.write(ctx, path, http.MethodPost, "api/labs", lab, nil)
+}
+
+func (s *labService) UpdateLab(ctx context.Context, path string, lab evengsdk.Lab) error {
+	name := labFileName(path, lab.Name)
+	lab.Path, _ = splitLabURL(path)
+	return s.client.write(ctx, path, http.MethodPut, "api/labs"+lab.Path+url.QueryEscape(name)+".unl", lab, nil)
+}
+
+func (s *labService) DeleteLab(ctx context.Context, path string) error {
+	return s.client.write(ctx, path, http.MethodDelete, labURL(path), nil, nil)
+}
+
+func (s *labService) MoveLab(ctx context.Context, path string, newPath string) error {
+	defer s.client.cache.invalidate(newPath)
+	if strings.Contains(newPath[strings.LastIndex(newPath, "/")+1:], ".unl") {
+		newPath = newPath[:strings.LastIndex(newPath, "/")+1]
+	}
+	return s.client.write(ctx, path, http.MethodPut, labURL(path)+"/move", map[string]string{"path": newPath}, nil)
+}
+
+// GetTopology returns the topology of the lab. The response is cached until the lab is written to.
+func (s *labService) GetTopology(ctx context.Context, path string) ([]map[string]interface{}, error) {
+	return cached(s.client.cache, path, "topology", func() ([]map[string]interface{}, error) {
+		return get[[]map[string]interface{}](ctx, s.client, labURL(path)+"/topology")
+	})
+}
+
+func (s *labService) CloseLab(ctx context.Context) error {
+	return s.client.do(ctx, http.MethodDelete, "api/labs/close", nil, nil)
+}
+
+type nodeService struct {
+	client *Client
+}
+
+// nodeURL returns the URL of the node of the lab at path.
+func nodeURL(path string, node int) string {
+	return nodesURL(path) + "/" + strconv.Itoa(node)
+}
+
+func (s *nodeService) GetNodes(ctx context.Context, path string) (map[string]evengsdk.Node, error) {
+	return get[map[string]evengsdk.Node](ctx, s.client, nodesURL(path))
+}
+
+func (s *nodeService) GetNode(ctx context.Context, path string, node int) (*evengsdk.Node, error) {
+	result, err := get[*evengsdk.Node](ctx, s.client, nodeURL(path, node))
+	if err != nil {
+		return nil, err
+	}
+	result.Id = node
+	return result, nil
+}
+
+func (s *nodeService) DeleteNode(ctx context.Context, path string, node int) error {
+	return s.client.write(ctx, path, http.MethodDelete, nodeURL(path, node), nil, nil)
+}
+
+// StartNodes starts every node of the lab. The Pro version has no endpoint to do so, so its
+// nodes are started one by one.
+func (s *nodeService) StartNodes(ctx context.Context, path string) error {
+	if !s.client.IsPro() {
+		dir, name := splitLabURL(path)
+		return s.client.write(ctx, path, http.MethodGet, "api/labs/"+dir[1:]+name+"/nodes/start", nil, nil)
+	}
+	nodes, err := getNodesById(ctx, s.client, path)
+	if err != nil {
+		return err
+	}
+	for id := range nodes {
+		if err := s.StartNode(ctx, path, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nodeService) StopNodes(ctx context.Context, path string) error {
+	return s.client.write(ctx, path, http.MethodGet, nodesURL(path)+"/stop", nil, nil)
+}
+
+func (s *nodeService) StartNode(ctx context.Context, path string, node int) error {
+	return s.client.write(ctx, path, http.MethodGet, nodeURL(path, node)+"/start", nil, nil)
+}
+
+func (s *nodeService) StopNode(ctx context.Context, path string, node int) error {
+	return s.client.write(ctx, path, http.MethodGet, nodeURL(path, node)+"/stop", nil, nil)
+}
+
+// GetNodeInterfaces returns the interfaces of the node. The response is cached until the lab
+// is written to.
+func (s *nodeService) GetNodeInterfaces(ctx context.Context, path string, node int) (*evengsdk.Interfaces, error) {
+	return cached(s.client.cache, path, "interfaces/"+strconv.Itoa(node), func() (*evengsdk.Interfaces, error) {
+		return get[*evengsdk.Interfaces](ctx, s.client, nodeURL(path, node)+"/interfaces")
+	})
+}
+
+// GetNodeInterface returns the index and the ethernet interface with the specified name of the node.
+func (s *nodeService) GetNodeInterface(ctx context.Context, path string, node int, intf string) (int, evengsdk.Interface, error) {
+	interfaces, err := s.GetNodeInterfaces(ctx, path, node)
+	if err != nil {
+		return 0, evengsdk.Interface{}, err
+	}
+	for index, eth := range interfaces.Ethernet {
+		if eth.Name == intf {
+			return index, eth, nil
+		}
+	}
+	return 0, evengsdk.Interface{}, errors.New("Interface not found")
+}
+
+// UpdateNodeInterfaceName connects the ethernet interface with the specified name of the node to
+// the network, or disconnects it when network is 0.
+func (s *nodeService) UpdateNodeInterfaceName(ctx context.Context, path string, node int, intf string, network int) error {
+	index, _, err := s.GetNodeInterface(ctx, path, node, intf)
+	if err != nil {
+		return err
+	}
+	var value interface{} = network
+	if network == 0 {
+		value = ""
+	}
+	return s.client.write(ctx, path, http.MethodPut, nodeURL(path, node)+"/interfaces", map[string]interface{}{strconv.Itoa(index): value}, nil)
+}
+
+// UpdateNodeInterfaceStyleByName sets the style of the link of the ethernet interface with the
+// specified name of the node. Link styles are only available in the Pro version.
+func (s *nodeService) UpdateNodeInterfaceStyleByName(ctx context.Context, path string, node int, intf string, style evengsdk.Style) error {
+	if !s.client.IsPro() {
+		return errors.New("This function is only available in the Pro version")
+	}
+	index, eth, err := s.GetNodeInterface(ctx, path, node, intf)
+	if err != nil {
+		return err
+	}
+	style.InterfaceId = strconv.Itoa(index)
+	style.Id = "network_id:" + strconv.Itoa(eth.NetworkId)
+	style.Node = strconv.Itoa(node)
+	style.Type = "ethernet"
+	return s.client.write(ctx, path, http.MethodPut, nodeURL(path, node)+"/style", style, nil)
+}
+
+// GetNodeConfig returns the startup configuration of the node. The Pro version reads the
+// configuration of a config set with a POST request, which does not modify the lab.
+func (s *nodeService) GetNodeConfig(ctx context.Context, path string, node int) (string, error) {
+	dir, name := splitLabURL(path)
+	configURL := "api/labs/" + dir + name + "/configs/" + strconv.Itoa(node)
+	var config struct {
+		Data string `json:"data"`
+	}
+	var err error
+	if s.client.IsPro() {
+		err = s.client.query(ctx, configURL, map[string]string{"cfsid": "default"}, &config)
+	} else {
+		err = s.client.do(ctx, http.MethodGet, configURL, nil, &config)
+	}
+	return config.Data, err
+}
+
+func (s *nodeService) UpdateNodeConfig(ctx context.Context, path string, node int, config string) error {
+	dir, name := splitLabURL(path)
+	payload := map[string]string{"data": config}
+	if s.client.IsPro() {
+		payload["cfsid"] = "default"
+	}
+	return s.client.write(ctx, path, http.MethodPut, "api/labs/"+dir+name+"/configs/"+strconv.Itoa(node), payload, nil)
+}
+
+// GetTemplates returns the description of every node template, keyed by template name.
+// Templates do not belong to a lab, so the response is cached under the empty lab path.
+func (s *nodeService) GetTemplates(ctx context.Context) (map[string]string, error) {
+	return cached(s.client.cache, "", "templates", func() (map[string]string, error) {
+		return get[map[string]string](ctx, s.client, "api/list/templates/")
+	})
+}
+
+// GetTemplate returns the template with the given name. The response is cached like GetTemplates.
+func (s *nodeService) GetTemplate(ctx context.Context, name string) (map[string]interface{}, error) {
+	return cached(s.client.cache, "", "template/"+name, func() (map[string]interface{}, error) {
+		return get[map[string]interface{}](ctx, s.client, "api/list/templates/"+name)
+	})
+}
+
+type networkService struct {
+	client *Client
+}
+
+// networksURL returns the URL of the networks of the lab at path.
+func networksURL(path string) string {
+	dir, name := splitLabURL(path)
+	return "api/labs/" + dir + name + "/networks"
+}
+
+func (s *networkService) GetNetwork(ctx context.Context, path string, id int) (evengsdk.Network, error) {
+	return get[evengsdk.Network](ctx, s.client, networksURL(path)+"/"+strconv.Itoa(id))
+}
+
+// CreateNetwork creates the network and sets its Id.
+func (s *networkService) CreateNetwork(ctx context.Context, path string, network *evengsdk.Network) error {
+	var created struct {
+		Id int `json:"id"`
+	}
+	err := s.client.write(ctx, path, http.MethodPost, networksURL(path), network, &created)
+	if err != nil {
+		return err
+	}
+	network.Id = created.Id
+	return nil
+}
+
+func (s *networkService) UpdateNetwork(ctx context.Context, path string, network *evengsdk.Network) error {
+	return s.client.write(ctx, path, http.MethodPut, networksURL(path)+"/"+strconv.Itoa(network.Id), network, nil)
+}
+
+func (s *networkService) DeleteNetwork(ctx context.Context, path string, id int) error {
+	return s.client.write(ctx, path, http.MethodDelete, networksURL(path)+"/"+strconv.Itoa(id), nil, nil)
+}
+
+type folderService struct {
+	client *Client
+}
+
+func (s *folderService) GetFolder(ctx context.Context, path string) (*evengsdk.Folders, error) {
+	return get[*evengsdk.Folders](ctx, s.client, "api/folders"+path)
+}
+
+func (s *folderService) CreateFolder(ctx context.Context, path string) error {
+	i := strings.LastIndex(path, "/") + 1
+	folder := evengsdk.Folder{
+		Name: path[i:],
+		Path: path[:i],
+	}
+	return s.client.do(ctx, http.MethodPost, "api/folders", folder, nil)
+}
+
+func (s *folderService) UpdateFolder(ctx context.Context, path string, folder evengsdk.Folder) error {
+	// Labs of the folder are moved or deleted along with it.
+	defer s.client.cache.invalidateAll()
+	return s.client.do(ctx, http.MethodPut, "api/folders/"+path, folder, nil)
+}
+
+func (s *folderService) DeleteFolder(ctx context.Context, path string) error {
+	// Labs of the folder are moved or deleted along with it.
+	defer s.client.cache.invalidateAll()
+	return s.client.do(ctx, http.MethodDelete, "api/folders"+path, nil, nil)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// nodeConfigTemplateData is the data config_file templates are rendered with.
+type nodeConfigTemplateData struct {
+	// Name is the name of the node.
+	Name string
+	// Id is the Id of the node in its lab.
+	Id int64
+	// Interfaces are the names of the interfaces of the node.
+	Interfaces nodeConfigTemplateInterfaces
+	// Vars are the config_vars of the node.
+	Vars map[string]string
+}
+
+type nodeConfigTemplateInterfaces struct {
+	Ethernet []string
+	Serial   []string
+}
+
+// parseNodeConfigFile reads and parses the config_file template.
+func parseNodeConfigFile(file string) (*template.Template, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(file).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// renderNodeConfigFile renders the config_file of the node with its name, its config_vars and
+// the given Id and interfaces.
+func renderNodeConfigFile(ctx context.Context, model nodeResourceModel, nodeId int64, interfaces interfacesResourceModel) (string, error) {
+	tmpl, err := parseNodeConfigFile(model.ConfigFile.ValueString())
+	if err != nil {
+		return "", err
+	}
+	data := nodeConfigTemplateData{
+		Name: model.Name.ValueString(),
+		Id:   nodeId,
+		Vars: map[string]string{},
+	}
+	if !model.ConfigVars.IsNull() {
+		diags := model.ConfigVars.ElementsAs(ctx, &data.Vars, false)
+		if diags.HasError() {
+			return "", fmt.Errorf("failed to read config_vars: %v", diags)
+		}
+	}
+	diags := interfaces.Ethernet.ElementsAs(ctx, &data.Interfaces.Ethernet, false)
+	if diags.HasError() {
+		return "", fmt.Errorf("failed to read ethernet interfaces: %v", diags)
+	}
+	diags = interfaces.Serial.ElementsAs(ctx, &data.Interfaces.Serial, false)
+	if diags.HasError() {
+		return "", fmt.Errorf("failed to read serial interfaces: %v", diags)
+	}
+	var config strings.Builder
+	if err := tmpl.Execute(&config, data); err != nil {
+		return "", err
+	}
+	return config.String(), nil
+}
+
+// interfacesFromObject converts the interfaces attribute of the node back to its model.
+func interfacesFromObject(ctx context.Context, object basetypes.ObjectValue) (interfacesResourceModel, error) {
+	var interfaces interfacesResourceModel
+	diags := object.As(ctx, &interfaces, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return interfacesResourceModel{}, fmt.Errorf("failed to read interfaces: %v", diags)
+	}
+	return interfaces, nil
+}
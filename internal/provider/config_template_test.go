@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRenderNodeConfigFileInterfaceOrder(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.tmpl")
+	err := os.WriteFile(file, []byte("hostname {{ .Name }}\n{{ range .Interfaces.Ethernet }}interface {{ . }}\n{{ end }}{{ range .Interfaces.Serial }}interface {{ . }}\n{{ end }}"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := nodeResourceModel{
+		Name:       types.StringValue("router"),
+		ConfigFile: types.StringValue(file),
+		ConfigVars: types.MapNull(types.StringType),
+	}
+	interfaces := &evengsdk.Interfaces{
+		Ethernet: evengsdk.InterfaceEntry{},
+		Serial:   evengsdk.InterfaceEntry{},
+	}
+	expected := "hostname router\n"
+	for i := 0; i < 16; i++ {
+		interfaces.Ethernet[i] = evengsdk.Interface{Name: fmt.Sprintf("Gi0/%d", i)}
+		expected += fmt.Sprintf("interface Gi0/%d\n", i)
+	}
+	for i := 0; i < 4; i++ {
+		interfaces.Serial[i] = evengsdk.Interface{Name: fmt.Sprintf("Se0/%d", i)}
+		expected += fmt.Sprintf("interface Se0/%d\n", i)
+	}
+
+	for i := 0; i < 2; i++ {
+		ints, err := newInterfacesModel(interfaces)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := renderNodeConfigFile(context.Background(), model, 1, ints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config != expected {
+			t.Fatalf("render %d: expected\n%s\ngot\n%s", i, expected, config)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = nodeConfigType{}
+	_ basetypes.StringValuableWithSemanticEquals = nodeConfigValue{}
+)
+
+// nodeConfigType is the type of node startup configurations. Two configurations are equal when
+// they only differ by line endings, trailing whitespace and trailing empty lines, the noise
+// EVE-NG and editors add to them.
+type nodeConfigType struct {
+	basetypes.StringType
+}
+
+func (t nodeConfigType) String() string {
+	return "nodeConfigType"
+}
+
+func (t nodeConfigType) Equal(o attr.Type) bool {
+	other, ok := o.(nodeConfigType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t nodeConfigType) ValueType(_ context.Context) attr.Value {
+	return nodeConfigValue{}
+}
+
+func (t nodeConfigType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return nodeConfigValue{StringValue: in}, nil
+}
+
+func (t nodeConfigType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return nodeConfigValue{StringValue: stringValue}, nil
+}
+
+// nodeConfigValue is a value of nodeConfigType.
+type nodeConfigValue struct {
+	basetypes.StringValue
+}
+
+func newNodeConfigValue(config string) nodeConfigValue {
+	return nodeConfigValue{StringValue: basetypes.NewStringValue(config)}
+}
+
+func newNodeConfigNull() nodeConfigValue {
+	return nodeConfigValue{StringValue: basetypes.NewStringNull()}
+}
+
+func (v nodeConfigValue) Type(_ context.Context) attr.Type {
+	return nodeConfigType{}
+}
+
+func (v nodeConfigValue) Equal(o attr.Value) bool {
+	other, ok := o.(nodeConfigValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v nodeConfigValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(nodeConfigValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, got %T. Report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+	return normalizeNodeConfig(v.ValueString()) == normalizeNodeConfig(newValue.ValueString()), diags
+}
+
+// normalizeNodeConfig converts the line endings of a configuration to LF and removes trailing
+// whitespace and trailing empty lines.
+func normalizeNodeConfig(config string) string {
+	config = strings.ReplaceAll(config, "\r\n", "\n")
+	config = strings.ReplaceAll(config, "\r", "\n")
+	lines := strings.Split(config, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// nodeConfigSha256 returns the hex encoded SHA-256 of the normalized configuration.
+func nodeConfigSha256(config string) string {
+	sum := sha256.Sum256([]byte(normalizeNodeConfig(config)))
+	return hex.EncodeToString(sum[:])
+}
+
+// keepEmptyNodeConfig returns the configuration the server reported, unless the server has no
+// configuration and the configuration of Terraform is semantically empty. EVE-NG does not tell
+// an empty configuration from no configuration, which would otherwise turn config = "" into a
+// permanent diff.
+func keepEmptyNodeConfig(terraform, server nodeConfigValue) nodeConfigValue {
+	if server.IsNull() && !terraform.IsNull() && !terraform.IsUnknown() && normalizeNodeConfig(terraform.ValueString()) == "" {
+		return terraform
+	}
+	return server
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeNodeConfig(t *testing.T) {
+	for _, test := range []struct {
+		config string
+		want   string
+	}{
+		{"", ""},
+		{"\n\n", ""},
+		{"hostname r1", "hostname r1"},
+		{"hostname r1\n", "hostname r1"},
+		{"hostname r1\r\ninterface e0\r\n", "hostname r1\ninterface e0"},
+		{"hostname r1\rinterface e0\r", "hostname r1\ninterface e0"},
+		{"hostname r1  \t\ninterface e0 \n\n\n", "hostname r1\ninterface e0"},
+		{"  indented\n\n!\n", "  indented\n\n!"},
+	} {
+		if got := normalizeNodeConfig(test.config); got != test.want {
+			t.Errorf("normalizeNodeConfig(%q) = %q, want %q", test.config, got, test.want)
+		}
+	}
+}
+
+func TestNodeConfigSemanticEquals(t *testing.T) {
+	equal, diags := newNodeConfigValue("hostname r1\r\n").StringSemanticEquals(context.Background(), newNodeConfigValue("hostname r1  "))
+	if diags.HasError() || !equal {
+		t.Errorf("expected configurations differing by whitespace to be equal")
+	}
+	equal, diags = newNodeConfigValue("hostname r1").StringSemanticEquals(context.Background(), newNodeConfigValue("hostname r2"))
+	if diags.HasError() || equal {
+		t.Errorf("expected different configurations not to be equal")
+	}
+	if nodeConfigSha256("hostname r1\r\n") != nodeConfigSha256("hostname r1") {
+		t.Errorf("expected the SHA-256 of equal configurations to match")
+	}
+}
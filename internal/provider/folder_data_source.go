@@ -6,7 +6,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/CorentinPtrl/evengsdk"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -22,7 +21,7 @@ func NewFolderDataSource() datasource.DataSource {
 }
 
 type folderDataSource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 type FolderDataSourceModel struct {
@@ -54,11 +53,11 @@ func (d *folderDataSource) Configure(_ context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -118,7 +117,7 @@ func (d *folderDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	folders, err := d.client.Folder.GetFolder(state.Path)
+	folders, err := d.client.Folder.GetFolder(ctx, state.Path)
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
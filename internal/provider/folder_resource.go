@@ -25,7 +25,7 @@ func NewFolderResource() resource.Resource {
 
 // folderResource is the resource implementation.
 type folderResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 // FolderResourceModel describes the resource data model.
@@ -46,11 +46,11 @@ func (r *folderResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -79,7 +79,7 @@ func (r *folderResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	err := r.client.Folder.CreateFolder(plan.Path)
+	err := r.client.Folder.CreateFolder(ctx, plan.Path)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create folder", err.Error())
 		return
@@ -101,7 +101,7 @@ func (r *folderResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	_, err := r.client.Folder.GetFolder(state.Path)
+	_, err := r.client.Folder.GetFolder(ctx, state.Path)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read folder", err.Error())
 		return
@@ -129,7 +129,7 @@ func (r *folderResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	err := r.client.Folder.UpdateFolder(state.Path, evengsdk.Folder{
+	err := r.client.Folder.UpdateFolder(ctx, state.Path, evengsdk.Folder{
 		Path: plan.Path,
 	})
 
@@ -154,7 +154,7 @@ func (r *folderResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.Folder.DeleteFolder(state.Path)
+	err := r.client.Folder.DeleteFolder(ctx, state.Path)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete folder", err.Error())
 		return
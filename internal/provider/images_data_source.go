@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource                   = &imagesDataSource{}
+	_ datasource.DataSourceWithConfigure      = &imagesDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &imagesDataSource{}
+)
+
+func NewImagesDataSource() datasource.DataSource {
+	return &imagesDataSource{}
+}
+
+type imagesDataSource struct {
+	client *Client
+}
+
+type ImagesDataSourceModel struct {
+	Template  string       `tfsdk:"template"`
+	NameRegex types.String `tfsdk:"name_regex"`
+	Images    []string     `tfsdk:"images"`
+	Latest    types.String `tfsdk:"latest"`
+}
+
+func (d *imagesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_images"
+}
+
+func (d *imagesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *imagesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the images installed for a node template.",
+		Attributes: map[string]schema.Attribute{
+			"template": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the template (e.g. vios).",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the image names must match.",
+			},
+			"images": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Names of the installed images matching name_regex, from the oldest to the newest version.",
+			},
+			"latest": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the image with the highest version, null when no image matches.",
+			},
+		},
+	}
+}
+
+func (d *imagesDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config ImagesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.NameRegex.IsNull() || config.NameRegex.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(config.NameRegex.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+	}
+}
+
+func (d *imagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ImagesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tmpl, err := getNodeTemplate(ctx, d.client, state.Template)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read template", err.Error())
+		return
+	}
+
+	var re *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		re, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	state.Images = []string{}
+	for _, image := range tmpl.Images() {
+		if re == nil || re.MatchString(image) {
+			state.Images = append(state.Images, image)
+		}
+	}
+	sort.SliceStable(state.Images, func(i, j int) bool {
+		return compareVersions(state.Images[i], state.Images[j]) < 0
+	})
+	state.Latest = types.StringNull()
+	if len(state.Images) > 0 {
+		state.Latest = types.StringValue(state.Images[len(state.Images)-1])
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// compareVersions compares two image names the way versions are ordered: runs of digits are
+// compared by their numeric value and the rest byte by byte, so that vios-15.10 comes after
+// vios-15.9.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		aDigits, bDigits := isDigit(a[0]), isDigit(b[0])
+		if aDigits && bDigits {
+			aRun, bRun := leadingDigits(a), leadingDigits(b)
+			aNum, bNum := trimLeadingZeros(aRun), trimLeadingZeros(bRun)
+			if len(aNum) != len(bNum) {
+				return len(aNum) - len(bNum)
+			}
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+			a, b = a[len(aRun):], b[len(bRun):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func trimLeadingZeros(s string) string {
+	for len(s) > 1 && s[0] == '0' {
+		s = s[1:]
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccEveImagesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccImagesDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eveng_images.test", "template", "vpcs"),
+					resource.TestCheckResourceAttrSet("data.eveng_images.test", "images.#"),
+					resource.TestCheckResourceAttr("data.eveng_images.test", "name_regex", "^none$"),
+					resource.TestCheckResourceAttr("data.eveng_images.test", "images.#", "0"),
+					resource.TestCheckNoResourceAttr("data.eveng_images.test", "latest"),
+				),
+			},
+			// The template of the node acceptance tests has an image installed.
+			{
+				Config: testAccImagesDataSourceInstalledConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eveng_images.test", "template", testAccNodeTemplates["qemu"]),
+					resource.TestCheckResourceAttrSet("data.eveng_images.test", "latest"),
+					testAccCheckImagesLatest("data.eveng_images.test"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckImagesLatest checks that latest is the last of the images, which are sorted from
+// the oldest to the newest version.
+func testAccCheckImagesLatest(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+		count, err := strconv.Atoi(rs.Primary.Attributes["images.#"])
+		if err != nil || count == 0 {
+			return fmt.Errorf("expected images to match, got %q", rs.Primary.Attributes["images.#"])
+		}
+		newest := rs.Primary.Attributes[fmt.Sprintf("images.%d", count-1)]
+		if latest := rs.Primary.Attributes["latest"]; latest != newest {
+			return fmt.Errorf("expected latest to be the newest image %q, got %q", newest, latest)
+		}
+		return nil
+	}
+}
+
+const testAccImagesDataSourceConfig = `
+data "eveng_images" "test" {
+  template   = "vpcs"
+  name_regex = "^none$"
+}
+`
+
+var testAccImagesDataSourceInstalledConfig = fmt.Sprintf(`
+data "eveng_images" "test" {
+  template   = %q
+  name_regex = ".*"
+}
+`, testAccNodeTemplates["qemu"])
+
+func TestCompareVersions(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"15.9", "15.10", -1},
+		{"15.10", "15.9", 1},
+		{"vios-15.6", "vios-15.6.1", -1},
+		{"vios-15.6.1", "vios-15.6", 1},
+		{"vios-15.6", "vios-15.6", 0},
+		{"vios-15.06", "vios-15.6", 0},
+		{"csr1000v-17.3", "vios-15.6", -1},
+		{"", "1", -1},
+	} {
+		got := compareVersions(test.a, test.b)
+		if (got < 0 && test.want >= 0) || (got > 0 && test.want <= 0) || (got == 0 && test.want != 0) {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign of %d", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/CorentinPtrl/evengsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -15,8 +16,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &labResource{}
-	_ resource.ResourceWithConfigure = &labResource{}
+	_ resource.Resource                = &labResource{}
+	_ resource.ResourceWithConfigure   = &labResource{}
+	_ resource.ResourceWithImportState = &labResource{}
 )
 
 // NewLabResource is a helper function to simplify the provider implementation.
@@ -26,7 +28,7 @@ func NewLabResource() resource.Resource {
 
 // labResource is the resource implementation.
 type labResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 // labResourceModel describes the resource data model.
@@ -55,11 +57,11 @@ func (r *labResource) Configure(_ context.Context, req resource.ConfigureRequest
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -125,7 +127,14 @@ func (r *labResource) Create(ctx context.Context, req resource.CreateRequest, re
 		path = plan.FolderPath.ValueString()
 	}
 	path = path + "/" + plan.Name + ".unl"
-	err := r.client.Lab.CreateLab(path, evengsdk.Lab{
+	unlock, err := r.client.LockLab(ctx, path)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	err = r.client.Lab.CreateLab(ctx, path, evengsdk.Lab{
 		Author:      plan.Author.ValueString(),
 		Body:        plan.Body.ValueString(),
 		Description: plan.Description.ValueString(),
@@ -134,7 +143,7 @@ func (r *labResource) Create(ctx context.Context, req resource.CreateRequest, re
 		resp.Diagnostics.AddError("Failed to create lab", err.Error())
 		return
 	}
-	lab, err := r.client.Lab.GetLab(path)
+	lab, err := r.client.Lab.GetLab(ctx, path)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read lab", err.Error())
 		return
@@ -159,7 +168,7 @@ func (r *labResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	lab, err := r.client.Lab.GetLab(state.Path.ValueString())
+	lab, err := r.client.Lab.GetLab(ctx, state.Path.ValueString())
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
@@ -192,12 +201,19 @@ func (r *labResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	err := r.MoveLab(&plan, &state)
+	unlock, err := r.client.LockLab(ctx, state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	err = r.MoveLab(ctx, &plan, &state)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to move lab", err.Error())
 		return
 	}
-	err = r.client.Lab.UpdateLab(state.Path.ValueString(), evengsdk.Lab{
+	err = r.client.Lab.UpdateLab(ctx, state.Path.ValueString(), evengsdk.Lab{
 		Name:        plan.Name,
 		Author:      plan.Author.ValueString(),
 		Body:        plan.Body.ValueString(),
@@ -208,7 +224,7 @@ func (r *labResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 	state.Path = basetypes.NewStringValue(plan.FolderPath.ValueString() + "/" + plan.Name + ".unl")
-	lab, err := r.client.Lab.GetLab(state.Path.ValueString())
+	lab, err := r.client.Lab.GetLab(ctx, state.Path.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read lab", err.Error())
 		return
@@ -236,24 +252,77 @@ func (r *labResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := r.client.Lab.DeleteLab(state.Path.ValueString())
+
+	unlock, err := r.client.LockLab(ctx, state.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+	err = r.client.Lab.DeleteLab(ctx, state.Path.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete lab", err.Error())
 		return
 	}
 }
 
-func (r *labResource) MoveLab(plan *labResourceModel, state *labResourceModel) error {
+// ImportState imports an existing lab using its full path (e.g. /folder/lab.unl).
+func (r *labResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	folderPath, name, err := splitLabPath(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	path := folderPath + "/" + name + ".unl"
+	lab, err := r.client.Lab.GetLab(ctx, path)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to import lab", fmt.Sprintf("Unable to read lab %s: %s", path, err))
+		return
+	}
+	state := labResourceModel{
+		FolderPath:  stringToBasetype(folderPath),
+		Path:        basetypes.NewStringValue(path),
+		Author:      stringToBasetype(lab.Author),
+		Body:        stringToBasetype(lab.Body),
+		Description: stringToBasetype(lab.Description),
+		Filename:    basetypes.NewStringValue(lab.Filename),
+		Name:        name,
+		Version:     basetypes.NewStringValue(lab.Version.String()),
+		Id:          basetypes.NewStringValue(lab.Id),
+	}
+	diags := resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// splitLabPath splits a lab path into the folder_path and name attributes used by Create,
+// which builds the path as folder_path + "/" + name + ".unl". The root folder is returned as "".
+func splitLabPath(labPath string) (string, string, error) {
+	if !strings.HasSuffix(labPath, ".unl") {
+		return "", "", fmt.Errorf("lab path %q must point to a .unl file", labPath)
+	}
+	if !strings.HasPrefix(labPath, "/") {
+		labPath = "/" + labPath
+	}
+	sep := strings.LastIndex(labPath, "/")
+	folderPath := strings.TrimRight(labPath[:sep], "/")
+	name := strings.TrimSuffix(labPath[sep+1:], ".unl")
+	if name == "" {
+		return "", "", fmt.Errorf("lab path %q has an empty lab name", labPath)
+	}
+	return folderPath, name, nil
+}
+
+func (r *labResource) MoveLab(ctx context.Context, plan *labResourceModel, state *labResourceModel) error {
 	if plan.FolderPath.ValueString() != state.FolderPath.ValueString() {
 		path := plan.FolderPath.ValueString() + "/" + state.Name + ".unl"
-		otherLab, err := r.client.Lab.GetLab(plan.FolderPath.ValueString() + "/" + state.Name + ".unl")
+		otherLab, err := r.client.Lab.GetLab(ctx, plan.FolderPath.ValueString()+"/"+state.Name+".unl")
 		if err == nil && otherLab.Id != state.Id.ValueString() {
 			return fmt.Errorf("Lab already exists in the new folder")
 		} else if err == nil && otherLab.Id == state.Id.ValueString() {
 			state.Path = basetypes.NewStringValue(path)
 			return nil
 		}
-		err = r.client.Lab.MoveLab(state.Path.ValueString(), plan.FolderPath.ValueString())
+		err = r.client.Lab.MoveLab(ctx, state.Path.ValueString(), plan.FolderPath.ValueString())
 		if err != nil {
 			return err
 		}
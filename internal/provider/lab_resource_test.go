@@ -26,6 +26,13 @@ func TestAccLabResource(t *testing.T) {
 					resource.TestCheckResourceAttr("eveng_lab.test", "description", "terraform acceptance test"),
 				),
 			},
+			// ImportState testing
+			{
+				ResourceName:      "eveng_lab.test",
+				ImportState:       true,
+				ImportStateId:     "/acceptance-test.unl",
+				ImportStateVerify: true,
+			},
 			// Update and Read testing
 			{
 				Config: testAccLabResourceConfig("acceptance-test-update"),
@@ -52,3 +59,35 @@ resource "eveng_lab" "test" {
 }
 `, configurableAttribute)
 }
+
+func TestSplitLabPath(t *testing.T) {
+	for _, test := range []struct {
+		labPath    string
+		folderPath string
+		name       string
+		err        bool
+	}{
+		{labPath: "/lab.unl", folderPath: "", name: "lab"},
+		{labPath: "lab.unl", folderPath: "", name: "lab"},
+		{labPath: "/labs/core.unl", folderPath: "/labs", name: "core"},
+		{labPath: "/labs/dc/core.unl", folderPath: "/labs/dc", name: "core"},
+		{labPath: "/labs//core.unl", folderPath: "/labs", name: "core"},
+		{labPath: "/labs/core", err: true},
+		{labPath: "/labs/.unl", err: true},
+	} {
+		folderPath, name, err := splitLabPath(test.labPath)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.labPath)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.labPath, err)
+			continue
+		}
+		if folderPath != test.folderPath || name != test.name {
+			t.Errorf("%q: expected %q and %q, got %q and %q", test.labPath, test.folderPath, test.name, folderPath, name)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+	"time"
+)
+
+const (
+	labStateRunning = "running"
+	labStateStopped = "stopped"
+	labStateMixed   = "mixed"
+)
+
+const defaultLabStateTimeout = 20 * time.Minute
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &labStateResource{}
+	_ resource.ResourceWithConfigure  = &labStateResource{}
+	_ resource.ResourceWithModifyPlan = &labStateResource{}
+)
+
+// NewLabStateResource is a helper function to simplify the provider implementation.
+func NewLabStateResource() resource.Resource {
+	return &labStateResource{}
+}
+
+// labStateResource is the resource implementation.
+type labStateResource struct {
+	client *Client
+}
+
+// labStateResourceModel describes the resource data model.
+type labStateResourceModel struct {
+	LabPath      types.String   `tfsdk:"lab_path"`
+	DesiredState types.String   `tfsdk:"desired_state"`
+	CurrentState types.String   `tfsdk:"current_state"`
+	Nodes        types.Map      `tfsdk:"nodes"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *labStateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lab_state"
+}
+
+// Configure sets the provider data for the resource.
+func (r *labStateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// Schema defines the schema for the resource.
+func (r *labStateResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the running state of every node of a lab. Destroying the resource stops the nodes.",
+		Attributes: map[string]schema.Attribute{
+			"lab_path": schema.StringAttribute{
+				Required:    true,
+				Description: "Path of the lab.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"desired_state": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(labStateRunning),
+				Validators: []validator.String{
+					stringvalidator.OneOf(labStateRunning, labStateStopped),
+				},
+				Description: "Desired state of the nodes of the lab, either \"running\" or \"stopped\".",
+			},
+			"current_state": schema.StringAttribute{
+				Computed: true,
+				Description: "State of the nodes of the lab as last read: \"running\" or \"stopped\" when every node agrees, \"mixed\" otherwise, " +
+					"and null when the lab has no node. A difference with desired_state plans an update.",
+			},
+			"nodes": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Current state of each node of the lab, keyed by node ID. The state of a single node is set with the state attribute of eveng_node.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				Delete:            true,
+				CreateDescription: "Time to wait for the nodes to change state on creation (e.g. \"30m\"). Defaults to \"20m\".",
+				UpdateDescription: "Time to wait for the nodes to change state on update (e.g. \"30m\"). Defaults to \"20m\".",
+				DeleteDescription: "Time to wait for the nodes to stop on destruction (e.g. \"30m\"). Defaults to \"20m\".",
+			}),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *labStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan labStateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultLabStateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	_, err = r.client.Lab.GetLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read lab", err.Error())
+		return
+	}
+	err = r.ApplyState(ctx, plan.LabPath.ValueString(), plan.DesiredState.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to change lab state", err.Error())
+		return
+	}
+	nodes, current, err := r.NewNodesModel(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read nodes", err.Error())
+		return
+	}
+	plan.Nodes = nodes
+	plan.CurrentState = newLabCurrentState(current)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *labStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state labStateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.Lab.GetLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	nodes, current, err := r.NewNodesModel(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read nodes", err.Error())
+		return
+	}
+	state.Nodes = nodes
+	state.CurrentState = newLabCurrentState(current)
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// ModifyPlan plans an update when the nodes drifted from desired_state, current_state and
+// nodes then being unknown until the nodes are started or stopped again.
+func (r *labStateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, state labStateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.DesiredState.IsUnknown() || !plan.LabPath.Equal(state.LabPath) {
+		return
+	}
+	if !state.CurrentState.IsNull() && !state.CurrentState.Equal(plan.DesiredState) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("current_state"), types.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("nodes"), types.MapUnknown(types.StringType))...)
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *labStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan labStateResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultLabStateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	err = r.ApplyState(ctx, plan.LabPath.ValueString(), plan.DesiredState.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to change lab state", err.Error())
+		return
+	}
+	nodes, current, err := r.NewNodesModel(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read nodes", err.Error())
+		return
+	}
+	plan.Nodes = nodes
+	plan.CurrentState = newLabCurrentState(current)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *labStateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state labStateResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultLabStateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	_, err = r.client.Lab.GetLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		tflog.Info(ctx, "Lab no longer exists, nothing to stop", map[string]interface{}{
+			"lab_path": state.LabPath.ValueString(),
+		})
+		return
+	}
+	err = r.ApplyState(ctx, state.LabPath.ValueString(), labStateStopped)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to stop nodes", err.Error())
+		return
+	}
+}
+
+// ApplyState starts or stops every node of the lab.
+func (r *labStateResource) ApplyState(ctx context.Context, labPath string, desiredState string) error {
+	tflog.Info(ctx, "Changing lab state", map[string]interface{}{
+		"lab_path":      labPath,
+		"desired_state": desiredState,
+	})
+	if desiredState == labStateStopped {
+		return r.client.Node.StopNodes(ctx, labPath)
+	}
+	_, err := startLab(ctx, r.client, labPath)
+	return err
+}
+
+// NewNodesModel returns the state of each node of the lab and the aggregated state of the lab:
+// "running" or "stopped" when every node agrees, "mixed" otherwise and "" when the lab has no node.
+func (r *labStateResource) NewNodesModel(ctx context.Context, labPath string) (types.Map, string, error) {
+	nodes, err := r.client.Node.GetNodes(ctx, labPath)
+	if err != nil {
+		return types.MapNull(types.StringType), "", err
+	}
+	states := make(map[string]string, len(nodes))
+	running := 0
+	for id, node := range nodes {
+		if node.Id != 0 {
+			id = strconv.Itoa(node.Id)
+		}
+		if isNodeRunning(node.Status) {
+			states[id] = labStateRunning
+			running++
+		} else {
+			states[id] = labStateStopped
+		}
+	}
+	model, diags := types.MapValueFrom(ctx, types.StringType, states)
+	if diags.HasError() {
+		return model, "", fmt.Errorf("failed to create nodes map")
+	}
+	switch {
+	case len(nodes) == 0:
+		return model, "", nil
+	case running == len(nodes):
+		return model, labStateRunning, nil
+	case running == 0:
+		return model, labStateStopped, nil
+	default:
+		return model, labStateMixed, nil
+	}
+}
+
+// newLabCurrentState returns the value of current_state for an aggregated state returned by
+// NewNodesModel.
+func newLabCurrentState(current string) types.String {
+	if current == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(current)
+}
+
+// isNodeRunning reports whether an EVE-NG node status denotes a running node.
+// EVE-NG uses 0 for stopped, 1 for stopped and locked, 2 for running and 3 for running and locked.
+func isNodeRunning(status int) bool {
+	return status >= 2
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccLabStateResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccLabStateResourceConfig("running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "lab_path", "/terraform-acceptance-test-lab-state.unl"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "desired_state", "running"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "current_state", "running"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "nodes.%", "1"),
+				),
+			},
+			// Update and Read testing
+			{
+				Config: testAccLabStateResourceConfig("stopped"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "desired_state", "stopped"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "current_state", "stopped"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "nodes.%", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccLabStateResourceVpcs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccLabStateResourceTypeConfig("running", "vpcs"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "desired_state", "running"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "current_state", "running"),
+					resource.TestCheckResourceAttr("eveng_lab_state.test", "nodes.%", "1"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccLabStateResourceConfig(desiredState string) string {
+	return testAccLabStateResourceTypeConfig(desiredState, "qemu")
+}
+
+func testAccLabStateResourceTypeConfig(desiredState string, nodeType string) string {
+	return fmt.Sprintf(`
+resource "eveng_lab" "test" {
+	name = "terraform-acceptance-test-lab-state"
+	author = "terraform-acctest"
+	body = "terraform acceptance test"
+	description = "terraform acceptance test"
+}
+
+resource "eveng_node" "test" {
+  lab_path = eveng_lab.test.path
+  name = "acceptance-test-vpc"
+  template = %[3]q
+  type = %[2]q
+}
+
+resource "eveng_lab_state" "test" {
+  lab_path = eveng_lab.test.path
+  desired_state = %[1]q
+
+  depends_on = [eveng_node.test]
+}
+`, desiredState, nodeType, testAccNodeTemplates[nodeType])
+}
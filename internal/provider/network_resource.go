@@ -9,6 +9,8 @@ import (
 	"github.com/CorentinPtrl/evengsdk"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -25,7 +27,7 @@ func NewNetworkResource() resource.Resource {
 
 // networkResource is the resource implementation.
 type networkResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 // NetworkResourceModel describes the resource data model.
@@ -52,11 +54,11 @@ func (r *networkResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -72,6 +74,9 @@ func (r *networkResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 			"lab_path": schema.StringAttribute{
 				Required:    true,
 				Description: "Path to the lab file.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"id": schema.Int64Attribute{
 				Computed:    true,
@@ -113,13 +118,20 @@ func (r *networkResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	network := r.NewNode(plan)
-	err := r.client.Network.CreateNetwork(plan.LabPath.ValueString(), &network)
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	network := r.NewNode(ctx, plan)
+	err = r.client.Network.CreateNetwork(ctx, plan.LabPath.ValueString(), &network)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to create network", err.Error())
 		return
 	}
-	rnet, err := r.NewModel(plan.LabPath.ValueString(), network.Id)
+	rnet, err := r.NewModel(ctx, plan.LabPath.ValueString(), network.Id)
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to read network", err.Error())
 		return
@@ -140,7 +152,7 @@ func (r *networkResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	rnet, err := r.NewModel(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	rnet, err := r.NewModel(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
@@ -168,15 +180,22 @@ func (r *networkResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	network := r.NewNode(plan)
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	network := r.NewNode(ctx, plan)
 	network.Id = int(state.Id.ValueInt64())
-	err := r.client.Network.UpdateNetwork(plan.LabPath.ValueString(), &network)
+	err = r.client.Network.UpdateNetwork(ctx, plan.LabPath.ValueString(), &network)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update network", err.Error())
 		return
 	}
 
-	rnet, err := r.NewModel(plan.LabPath.ValueString(), network.Id)
+	rnet, err := r.NewModel(ctx, plan.LabPath.ValueString(), network.Id)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read network", err.Error())
 		return
@@ -198,14 +217,21 @@ func (r *networkResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	err := r.client.Network.DeleteNetwork(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	err = r.client.Network.DeleteNetwork(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete network", err.Error())
 		return
 	}
 }
 
-func (r *networkResource) NewNode(model NetworkResourceModel) evengsdk.Network {
+func (r *networkResource) NewNode(ctx context.Context, model NetworkResourceModel) evengsdk.Network {
 	network := evengsdk.Network{}
 	if !model.Id.IsUnknown() {
 		network.Id = int(model.Id.ValueInt64())
@@ -229,11 +255,11 @@ func (r *networkResource) NewNode(model NetworkResourceModel) evengsdk.Network {
 	return network
 }
 
-func (r *networkResource) NewModel(labPath string, netId int) (NetworkResourceModel, error) {
+func (r *networkResource) NewModel(ctx context.Context, labPath string, netId int) (NetworkResourceModel, error) {
 	model := NetworkResourceModel{}
 	model.LabPath = types.StringValue(labPath)
 	model.Id = types.Int64Value(int64(netId))
-	net, err := r.client.Network.GetNetwork(labPath, netId)
+	net, err := r.client.Network.GetNetwork(ctx, labPath, netId)
 	if err != nil {
 		return model, err
 	}
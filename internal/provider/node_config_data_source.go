@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &nodeConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &nodeConfigDataSource{}
+)
+
+func NewNodeConfigDataSource() datasource.DataSource {
+	return &nodeConfigDataSource{}
+}
+
+type nodeConfigDataSource struct {
+	client *Client
+}
+
+type NodeConfigDataSourceModel struct {
+	LabPath string                  `tfsdk:"lab_path"`
+	NodeId  types.Int64             `tfsdk:"node_id"`
+	Export  types.Bool              `tfsdk:"export"`
+	Config  types.String            `tfsdk:"config"`
+	Nodes   []NodeConfigSourceModel `tfsdk:"nodes"`
+}
+
+type NodeConfigSourceModel struct {
+	Id     int64  `tfsdk:"id"`
+	Name   string `tfsdk:"name"`
+	Config string `tfsdk:"config"`
+}
+
+func (d *nodeConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_config"
+}
+
+func (d *nodeConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *nodeConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports the running configuration of the started nodes of a lab into their startup configuration, and reads the startup configurations.",
+		Attributes: map[string]schema.Attribute{
+			"lab_path": schema.StringAttribute{
+				Required:    true,
+				Description: "Path of the lab.",
+			},
+			"node_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Id of the node to read. When unset, every node of the lab is read.",
+			},
+			"export": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether the running configuration of the started nodes is exported before it is read. Defaults to true.",
+			},
+			"config": schema.StringAttribute{
+				Computed:    true,
+				Description: "Startup configuration of the node with node_id, null when node_id is unset.",
+			},
+			"nodes": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Startup configuration of the nodes read, sorted by Id.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Id of the node.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the node.",
+						},
+						"config": schema.StringAttribute{
+							Computed:    true,
+							Description: "Startup configuration of the node.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *nodeConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NodeConfigDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unlock, err := d.client.LockLab(ctx, state.LabPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	nodes, err := getNodesById(ctx, d.client, state.LabPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get nodes", err.Error())
+		return
+	}
+
+	state.Nodes = []NodeConfigSourceModel{}
+	for _, node := range nodes {
+		if !state.NodeId.IsNull() && int64(node.Id) != state.NodeId.ValueInt64() {
+			continue
+		}
+		// Only started nodes have a running configuration to export.
+		if (state.Export.IsNull() || state.Export.ValueBool()) && isNodeRunning(node.Status) {
+			err = d.client.Node.ExportNodeConfig(ctx, state.LabPath, node.Id)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to export node config", fmt.Sprintf("Node %d: %s", node.Id, err))
+				return
+			}
+		}
+		config, err := d.client.Node.GetNodeConfig(ctx, state.LabPath, node.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get node config", fmt.Sprintf("Node %d: %s", node.Id, err))
+			return
+		}
+		state.Nodes = append(state.Nodes, NodeConfigSourceModel{
+			Id:     int64(node.Id),
+			Name:   node.Name,
+			Config: config,
+		})
+	}
+	sort.Slice(state.Nodes, func(i, j int) bool {
+		return state.Nodes[i].Id < state.Nodes[j].Id
+	})
+
+	state.Config = types.StringNull()
+	if !state.NodeId.IsNull() {
+		if len(state.Nodes) == 0 {
+			resp.Diagnostics.AddError("Node not found", fmt.Sprintf("Lab %s has no node with Id %d.", state.LabPath, state.NodeId.ValueInt64()))
+			return
+		}
+		state.Config = types.StringValue(state.Nodes[0].Config)
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccEveNodeConfigDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccNodeConfigDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eveng_node_config.test", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("data.eveng_node_config.test", "nodes.0.name", "acceptance-test"),
+					resource.TestMatchResourceAttr("data.eveng_node_config.test", "config", regexp.MustCompile("set pcname acceptance-test")),
+				),
+			},
+		},
+	})
+}
+
+const testAccNodeConfigDataSourceConfig = `
+resource "eveng_lab" "test" {
+	name = "terraform-acceptance-test-node-config"
+	author = "terraform-acctest"
+	body = "terraform acceptance test"
+	description = "terraform acceptance test"
+}
+
+resource "eveng_node" "test" {
+  lab_path = eveng_lab.test.path
+  name = "acceptance-test"
+  template = "vpcs"
+  type = "vpcs"
+  config = "set pcname acceptance-test"
+}
+
+data "eveng_node_config" "test" {
+  lab_path = eveng_lab.test.path
+  node_id  = eveng_node.test.id
+}
+`
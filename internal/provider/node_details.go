@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// nodeDetails is a node with the settings of the EVE-NG API that evengsdk.Node does not cover.
+type nodeDetails struct {
+	evengsdk.Node
+	QemuOptions apiString `json:"qemu_options,omitempty"`
+	QemuVersion apiString `json:"qemu_version,omitempty"`
+	QemuArch    apiString `json:"qemu_arch,omitempty"`
+	QemuNic     apiString `json:"qemu_nic,omitempty"`
+	Cpulimit    apiString `json:"cpulimit,omitempty"`
+	Firstmac    apiString `json:"firstmac,omitempty"`
+	Serial      apiString `json:"serial,omitempty"`
+	Nvram       apiString `json:"nvram,omitempty"`
+	Idlepc      apiString `json:"idlepc,omitempty"`
+	// The settings of docker nodes, EVE-NG Pro only.
+	DockerTag     apiString `json:"docker_tag,omitempty"`
+	DockerEnv     apiString `json:"docker_env,omitempty"`
+	DockerArgs    apiString `json:"docker_args,omitempty"`
+	DockerIp      apiString `json:"docker_ip,omitempty"`
+	DockerGateway apiString `json:"docker_gateway,omitempty"`
+	// Slots maps the slot numbers of a Dynamips node to the module they hold, EVE-NG
+	// sending them as slot0..slotN settings.
+	Slots map[string]string `json:"-"`
+}
+
+// slotPrefix prefixes the slot number in the name of the setting of a slot module.
+const slotPrefix = "slot"
+
+// nodeDetailsFields avoids the recursion of MarshalJSON and UnmarshalJSON into themselves.
+type nodeDetailsFields nodeDetails
+
+func (n nodeDetails) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(nodeDetailsFields(n))
+	if err != nil || len(n.Slots) == 0 {
+		return data, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for slot, module := range n.Slots {
+		fields[slotPrefix+slot] = module
+	}
+	return json.Marshal(fields)
+}
+
+func (n *nodeDetails) UnmarshalJSON(data []byte) error {
+	var fields nodeDetailsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	for key, raw := range settings {
+		slot, ok := slotNumber(key)
+		if !ok {
+			continue
+		}
+		var module apiString
+		if err := json.Unmarshal(raw, &module); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if fields.Slots == nil {
+			fields.Slots = make(map[string]string)
+		}
+		fields.Slots[slot] = string(module)
+	}
+	*n = nodeDetails(fields)
+	return nil
+}
+
+// slotNumber returns the slot number of a slot module setting such as slot1.
+func slotNumber(key string) (string, bool) {
+	slot, ok := strings.CutPrefix(key, slotPrefix)
+	if !ok || slot == "" {
+		return "", false
+	}
+	if _, err := strconv.Atoi(slot); err != nil {
+		return "", false
+	}
+	return slot, true
+}
+
+// apiString is a setting EVE-NG returns either as a JSON string or as a JSON number.
+type apiString string
+
+func (s *apiString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = apiString(str)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("expected a string or a number, got %s", data)
+	}
+	*s = apiString(number.String())
+	return nil
+}
+
+// apiInt64Value returns a numeric setting, null when EVE-NG does not report it for the node.
+func apiInt64Value(s apiString) types.Int64 {
+	value, err := strconv.ParseInt(string(s), 10, 64)
+	if err != nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(value)
+}
+
+// apiStringValue returns a setting, null when EVE-NG does not report it for the node.
+func apiStringValue(s apiString) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(string(s))
+}
+
+// formatDockerEnv formats environment variables the way EVE-NG stores them, one NAME=value
+// per line sorted by name.
+func formatDockerEnv(env map[string]string) string {
+	lines := make([]string, 0, len(env))
+	for name, value := range env {
+		lines = append(lines, name+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// parseDockerEnv is the reverse of formatDockerEnv.
+func parseDockerEnv(env string) map[string]string {
+	vars := make(map[string]string)
+	for _, line := range strings.Split(env, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(line, "=")
+		vars[name] = value
+	}
+	return vars
+}
+
+// nodesURL returns the URL of the nodes of the lab, relative to the API base URL, the way
+// evengsdk builds it.
+func nodesURL(path string) string {
+	dir, name := splitLabURL(path)
+	return "api/labs/" + dir + name + "/nodes"
+}
+
+// GetNodeDetails returns the node with the specified id, including the settings evengsdk.Node
+// does not cover.
+func (s *nodeService) GetNodeDetails(ctx context.Context, path string, node int) (*nodeDetails, error) {
+	details, err := get[*nodeDetails](ctx, s.client, nodeURL(path, node))
+	if err != nil {
+		return nil, err
+	}
+	details.Id = node
+	return details, nil
+}
+
+// CreateNodeDetails creates the node and sets its Id.
+func (s *nodeService) CreateNodeDetails(ctx context.Context, path string, node *nodeDetails) error {
+	var created struct {
+		Id *int `json:"id"`
+	}
+	err := s.client.write(ctx, path, http.MethodPost, nodesURL(path), node, &created)
+	if err != nil {
+		return err
+	}
+	if created.Id == nil {
+		return fmt.Errorf("no node id in response to node creation")
+	}
+	node.Id = *created.Id
+	return nil
+}
+
+// UpdateNodeDetails updates the node with the Id of node.
+func (s *nodeService) UpdateNodeDetails(ctx context.Context, path string, node *nodeDetails) error {
+	return s.client.write(ctx, path, http.MethodPut, nodeURL(path, node.Id), node, nil)
+}
+
+// ExportNodeConfig saves the running configuration of a started node as its startup
+// configuration.
+func (s *nodeService) ExportNodeConfig(ctx context.Context, path string, node int) error {
+	return s.client.write(ctx, path, http.MethodPut, nodeURL(path, node)+"/export", nil, nil)
+}
+
+// ExportNodesConfig saves the running configuration of every started node of the lab as its
+// startup configuration.
+func (s *nodeService) ExportNodesConfig(ctx context.Context, path string) error {
+	return s.client.write(ctx, path, http.MethodPut, nodesURL(path)+"/export", nil, nil)
+}
+
+// WipeNode deletes the NVRAM and disks of a stopped node, so that it boots from its startup
+// configuration again.
+func (s *nodeService) WipeNode(ctx context.Context, path string, node int) error {
+	return s.client.write(ctx, path, http.MethodGet, nodeURL(path, node)+"/wipe", nil, nil)
+}
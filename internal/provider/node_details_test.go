@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+
+	"github.com/CorentinPtrl/evengsdk"
+)
+
+func TestNodeDetailsMarshalSlots(t *testing.T) {
+	node := nodeDetails{
+		Node:  evengsdk.Node{Name: "r1", Type: "dynamips"},
+		Slots: map[string]string{"1": "PA-FE-TX", "2": ""},
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["slot1"] != "PA-FE-TX" || fields["slot2"] != "" {
+		t.Errorf("expected slot1 and slot2 in %s", data)
+	}
+	if fields["name"] != "r1" || fields["type"] != "dynamips" {
+		t.Errorf("expected the node settings in %s", data)
+	}
+	if _, ok := fields["Slots"]; ok {
+		t.Errorf("unexpected Slots field in %s", data)
+	}
+
+	data, err = json.Marshal(nodeDetails{Node: evengsdk.Node{Name: "r1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields = nil
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for key := range fields {
+		if _, ok := slotNumber(key); ok {
+			t.Errorf("unexpected slot %s in %s", key, data)
+		}
+	}
+}
+
+func TestNodeDetailsUnmarshalSlots(t *testing.T) {
+	var node nodeDetails
+	err := json.Unmarshal([]byte(`{"name":"r1","type":"dynamips","slot0":"C7200-IO-FE","slot1":"","slot2":null,"slots":"ignored","serial":4,"nvram":"128"}`), &node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"0": "C7200-IO-FE", "1": "", "2": ""}
+	if !maps.Equal(node.Slots, want) {
+		t.Errorf("expected slots %v, got %v", want, node.Slots)
+	}
+	if node.Name != "r1" || node.Serial != "4" || node.Nvram != "128" {
+		t.Errorf("unexpected node settings %+v", node)
+	}
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip nodeDetails
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(roundTrip.Slots, want) {
+		t.Errorf("expected slots %v after a round trip, got %v", want, roundTrip.Slots)
+	}
+
+	if err := json.Unmarshal([]byte(`{"slot1":{}}`), &node); err == nil {
+		t.Errorf("expected an error for a slot module that is not a string")
+	}
+}
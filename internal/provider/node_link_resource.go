@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/CorentinPtrl/evengsdk"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
@@ -15,18 +16,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float32default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &nodeLinkResource{}
-	_ resource.ResourceWithConfigure = &nodeLinkResource{}
+	_ resource.Resource                = &nodeLinkResource{}
+	_ resource.ResourceWithConfigure   = &nodeLinkResource{}
+	_ resource.ResourceWithImportState = &nodeLinkResource{}
 )
 
 // NewNodeLinkResource is a helper function to simplify the provider implementation.
@@ -36,7 +42,7 @@ func NewNodeLinkResource() resource.Resource {
 
 // nodeLinkResource is the resource implementation.
 type nodeLinkResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 type StyleResourceModel struct {
@@ -79,11 +85,11 @@ func (r *nodeLinkResource) Configure(_ context.Context, req resource.ConfigureRe
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -99,6 +105,9 @@ func (r *nodeLinkResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 			"lab_path": schema.StringAttribute{
 				Required:    true,
 				Description: "Path to the lab file.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"network_id": schema.Int64Attribute{
 				Optional: true,
@@ -236,17 +245,23 @@ func (r *nodeLinkResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
 	if plan.SourceNodeId.ValueInt64() == plan.TargetNodeId.ValueInt64() {
 		resp.Diagnostics.AddError("Cannot link a node to itself", "source and target node IDs are the same")
 		return
 	}
 
 	var id int64
-	var err error
 	if !plan.NetworkId.IsUnknown() {
-		id, err = r.MakeNodeLinkNet(plan, NodeLinkResourceModel{})
+		id, err = r.MakeNodeLinkNet(ctx, plan, NodeLinkResourceModel{})
 	} else {
-		id, err = r.MakeNodeLinkNode(plan, NodeLinkResourceModel{})
+		id, err = r.MakeNodeLinkNode(ctx, plan, NodeLinkResourceModel{})
 	}
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create node link (isNet=%t)", !plan.NetworkId.IsNull()), err.Error())
@@ -295,9 +310,9 @@ func (r *nodeLinkResource) Read(ctx context.Context, req resource.ReadRequest, r
 	var recreate bool
 	var err error
 	if state.TargetNodeId.IsNull() {
-		state, err, recreate = r.NewNodeLinkModelNet(state)
+		state, err, recreate = r.NewNodeLinkModelNet(ctx, state)
 	} else {
-		state, err, recreate = r.NewNodeLinkModelNode(state)
+		state, err, recreate = r.NewNodeLinkModelNode(ctx, state)
 	}
 	if recreate {
 		resp.State.RemoveResource(ctx)
@@ -333,6 +348,13 @@ func (r *nodeLinkResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
 	if plan.SourceNodeId.ValueInt64() == plan.TargetNodeId.ValueInt64() {
 		resp.Diagnostics.AddError("Cannot link a node to itself", "source and target node IDs are the same")
 		return
@@ -344,11 +366,10 @@ func (r *nodeLinkResource) Update(ctx context.Context, req resource.UpdateReques
 	}
 
 	var id int64
-	var err error
 	if !plan.NetworkId.IsUnknown() {
-		id, err = r.MakeNodeLinkNet(plan, state)
+		id, err = r.MakeNodeLinkNet(ctx, plan, state)
 	} else {
-		id, err = r.MakeNodeLinkNode(plan, state)
+		id, err = r.MakeNodeLinkNode(ctx, plan, state)
 	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update node link", err.Error())
@@ -375,14 +396,21 @@ func (r *nodeLinkResource) Delete(ctx context.Context, req resource.DeleteReques
 	if state.NetworkId.IsUnknown() {
 		return
 	}
+
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
 	if !state.TargetNodeId.IsNull() {
-		err := r.client.Network.DeleteNetwork(state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
+		err := r.client.Network.DeleteNetwork(ctx, state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
 		if err != nil {
 			resp.Diagnostics.AddError("Failed to delete node link", err.Error())
 			return
 		}
 	} else {
-		err := r.ensureInterfaceDeleted(state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
+		err := r.ensureInterfaceDeleted(ctx, state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
 		if err != nil {
 			resp.Diagnostics.AddError("Failed to delete node link", err.Error())
 			return
@@ -390,27 +418,204 @@ func (r *nodeLinkResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 }
 
-func (r *nodeLinkResource) MakeNodeLinkNet(plan NodeLinkResourceModel, state NodeLinkResourceModel) (int64, error) {
+// linkEndpoint is one end of a link as reported by the lab topology.
+type linkEndpoint struct {
+	NodeId int64
+	Port   string
+}
+
+// ImportState imports an existing link using an identifier of the form "lab_path:network_id"
+// or "lab_path:network_id:source_node_id:source_port".
+func (r *nodeLinkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	labPath, networkId, source, err := parseNodeLinkImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+
+	state, err := r.NewNodeLinkModelImport(ctx, labPath, networkId, source)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to import node link", err.Error())
+		return
+	}
+
+	var recreate bool
+	if state.TargetNodeId.IsNull() {
+		_, err, recreate = r.NewNodeLinkModelNet(ctx, state)
+	} else {
+		_, err, recreate = r.NewNodeLinkModelNode(ctx, state)
+	}
+	if recreate || err != nil {
+		resp.Diagnostics.AddError("Failed to import node link", fmt.Sprintf("Link on network %d could not be verified: %s", networkId, err))
+		return
+	}
+
+	if r.client.IsPro() && !state.TargetNodeId.IsNull() {
+		style := r.NewStyleModel(ctx, state)
+		state.Style = &style
+	}
+	tflog.Info(ctx, "Imported node link", map[string]interface{}{
+		"lab_path":       labPath,
+		"network_id":     networkId,
+		"source_node_id": state.SourceNodeId.ValueInt64(),
+		"target_node_id": state.TargetNodeId.ValueInt64(),
+	})
+	diags := resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// parseNodeLinkImportId splits an import identifier of the form "lab_path:network_id" or
+// "lab_path:network_id:source_node_id:source_port".
+func parseNodeLinkImportId(id string) (string, int64, *linkEndpoint, error) {
+	labPath, parts, ok := splitImportId(id, 3)
+	if !ok || !strings.HasSuffix(labPath, ".unl") {
+		labPath, parts, ok = splitImportId(id, 1)
+	}
+	if !ok {
+		return "", 0, nil, fmt.Errorf("expected import identifier with format \"lab_path:network_id\" or \"lab_path:network_id:source_node_id:source_port\", got %q", id)
+	}
+	if !strings.HasSuffix(labPath, ".unl") {
+		return "", 0, nil, fmt.Errorf("lab path %q must point to a .unl file", labPath)
+	}
+	networkId, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || networkId <= 0 {
+		return "", 0, nil, fmt.Errorf("network id %q must be a positive integer", parts[0])
+	}
+	if len(parts) == 1 {
+		return labPath, networkId, nil, nil
+	}
+	sourceNodeId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || sourceNodeId <= 0 {
+		return "", 0, nil, fmt.Errorf("source node id %q must be a positive integer", parts[1])
+	}
+	return labPath, networkId, &linkEndpoint{NodeId: sourceNodeId, Port: parts[2]}, nil
+}
+
+// NewNodeLinkModelImport reconstructs a link from the lab topology. Hidden networks (visibility 0)
+// are the point-to-point bridges created by MakeNodeLinkNode and become node-to-node links, any
+// other network becomes a link between a node interface and that network.
+func (r *nodeLinkResource) NewNodeLinkModelImport(ctx context.Context, labPath string, networkId int64, source *linkEndpoint) (NodeLinkResourceModel, error) {
+	model := NodeLinkResourceModel{
+		LabPath:      basetypes.NewStringValue(labPath),
+		NetworkId:    basetypes.NewInt64Value(networkId),
+		TargetNodeId: basetypes.NewInt64Null(),
+		TargetPort:   basetypes.NewStringNull(),
+	}
+	network, err := r.client.Network.GetNetwork(ctx, labPath, int(networkId))
+	if err != nil {
+		return model, fmt.Errorf("network %d not found in lab %s: %w", networkId, labPath, err)
+	}
+	endpoints, err := r.findLinkEndpoints(ctx, labPath, networkId)
+	if err != nil {
+		return model, err
+	}
+	if source != nil {
+		found := false
+		for _, endpoint := range endpoints {
+			if endpoint == *source {
+				found = true
+			}
+		}
+		if !found {
+			return model, fmt.Errorf("port %s of node %d is not connected to network %d", source.Port, source.NodeId, networkId)
+		}
+	}
+
+	if network.Visibility.String() != "0" {
+		if source == nil {
+			if len(endpoints) != 1 {
+				return model, fmt.Errorf("network %d has %d connected interfaces, use \"lab_path:network_id:source_node_id:source_port\" to select one", networkId, len(endpoints))
+			}
+			source = &endpoints[0]
+		}
+		model.SourceNodeId = basetypes.NewInt64Value(source.NodeId)
+		model.SourcePort = basetypes.NewStringValue(source.Port)
+		return model, nil
+	}
+
+	if len(endpoints) != 2 {
+		return model, fmt.Errorf("hidden network %d has %d connected interfaces, expected a point-to-point link", networkId, len(endpoints))
+	}
+	if source == nil {
+		source = &endpoints[0]
+		// Links created by this provider name their bridge "<source>_<index>_<target>_<index>".
+		if id, err := strconv.ParseInt(strings.Split(network.Name, "_")[0], 10, 64); err == nil && id == endpoints[1].NodeId {
+			source = &endpoints[1]
+		}
+	}
+	target := endpoints[0]
+	if target == *source {
+		target = endpoints[1]
+	}
+	model.SourceNodeId = basetypes.NewInt64Value(source.NodeId)
+	model.SourcePort = basetypes.NewStringValue(source.Port)
+	model.TargetNodeId = basetypes.NewInt64Value(target.NodeId)
+	model.TargetPort = basetypes.NewStringValue(target.Port)
+	return model, nil
+}
+
+// findLinkEndpoints returns the node interfaces attached to the network, ordered by node id.
+func (r *nodeLinkResource) findLinkEndpoints(ctx context.Context, labPath string, networkId int64) ([]linkEndpoint, error) {
+	topology, err := r.client.Lab.GetTopology(ctx, labPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topology: %w", err)
+	}
+	var endpoints []linkEndpoint
+	add := func(node interface{}, label interface{}) {
+		nodeName, ok := node.(string)
+		if !ok || !strings.HasPrefix(nodeName, "node") {
+			return
+		}
+		port, ok := label.(string)
+		if !ok || port == "" {
+			return
+		}
+		nodeId, err := strconv.ParseInt(strings.TrimPrefix(nodeName, "node"), 10, 64)
+		if err != nil {
+			return
+		}
+		endpoint := linkEndpoint{NodeId: nodeId, Port: port}
+		for _, e := range endpoints {
+			if e == endpoint {
+				return
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	for _, link := range topology {
+		if fmt.Sprintf("%v", link["network_id"]) != strconv.FormatInt(networkId, 10) && link["destination"] != fmt.Sprintf("network%d", networkId) {
+			continue
+		}
+		add(link["source"], link["source_label"])
+		add(link["destination"], link["destination_label"])
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].NodeId < endpoints[j].NodeId
+	})
+	return endpoints, nil
+}
+
+func (r *nodeLinkResource) MakeNodeLinkNet(ctx context.Context, plan NodeLinkResourceModel, state NodeLinkResourceModel) (int64, error) {
 	if ((plan.SourceNodeId.ValueInt64() != state.SourceNodeId.ValueInt64()) || plan.SourcePort.ValueString() != state.SourcePort.ValueString()) && state.SourceNodeId.ValueInt64() != 0 {
-		err := r.ensureInterfaceDeleted(plan.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
+		err := r.ensureInterfaceDeleted(ctx, plan.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
 		if err != nil {
 			return plan.NetworkId.ValueInt64(), err
 		}
 	}
-	err := r.client.Node.UpdateNodeInterfaceName(plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString(), int(plan.NetworkId.ValueInt64()))
+	err := r.client.Node.UpdateNodeInterfaceName(ctx, plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString(), int(plan.NetworkId.ValueInt64()))
 	if err != nil {
 		return plan.NetworkId.ValueInt64(), err
 	}
 	return plan.NetworkId.ValueInt64(), nil
 }
 
-func (r *nodeLinkResource) NewNodeLinkModelNet(state NodeLinkResourceModel) (NodeLinkResourceModel, error, bool) {
+func (r *nodeLinkResource) NewNodeLinkModelNet(ctx context.Context, state NodeLinkResourceModel) (NodeLinkResourceModel, error, bool) {
 	model := state
-	_, err := r.client.Network.GetNetwork(state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
+	_, err := r.client.Network.GetNetwork(ctx, state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
 	if err != nil {
 		return model, err, true
 	}
-	_, err = r.client.Node.GetNode(state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()))
+	_, err = r.client.Node.GetNode(ctx, state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()))
 	if err != nil {
 		model.SourceNodeId = basetypes.NewInt64Value(0)
 		model.SourcePort = basetypes.NewStringValue("")
@@ -419,7 +624,7 @@ func (r *nodeLinkResource) NewNodeLinkModelNet(state NodeLinkResourceModel) (Nod
 	if state.SourcePort.ValueString() == "" {
 		return model, nil, false
 	}
-	_, sourceInt, err := r.client.Node.GetNodeInterface(state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString())
+	_, sourceInt, err := r.client.Node.GetNodeInterface(ctx, state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString())
 	if err != nil {
 		model.SourcePort = basetypes.NewStringValue("")
 		return model, err, false
@@ -431,63 +636,100 @@ func (r *nodeLinkResource) NewNodeLinkModelNet(state NodeLinkResourceModel) (Nod
 	return model, nil, false
 }
 
-func (r *nodeLinkResource) MakeNodeLinkNode(plan NodeLinkResourceModel, state NodeLinkResourceModel) (int64, error) {
+func (r *nodeLinkResource) MakeNodeLinkNode(ctx context.Context, plan NodeLinkResourceModel, state NodeLinkResourceModel) (int64, error) {
 	if ((plan.SourceNodeId.ValueInt64() != state.SourceNodeId.ValueInt64()) || plan.SourcePort.ValueString() != state.SourcePort.ValueString()) && state.SourceNodeId.ValueInt64() != 0 {
-		err := r.ensureInterfaceDeleted(plan.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
+		err := r.ensureInterfaceDeleted(ctx, plan.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString(), int(state.NetworkId.ValueInt64()))
 		if err != nil {
 			return state.NetworkId.ValueInt64(), err
 		}
 	}
 	if ((plan.TargetNodeId.ValueInt64() != state.TargetNodeId.ValueInt64()) || plan.TargetPort.ValueString() != state.TargetPort.ValueString()) && state.TargetNodeId.ValueInt64() != 0 {
-		err := r.ensureInterfaceDeleted(plan.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()), state.TargetPort.ValueString(), int(state.NetworkId.ValueInt64()))
+		err := r.ensureInterfaceDeleted(ctx, plan.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()), state.TargetPort.ValueString(), int(state.NetworkId.ValueInt64()))
 		if err != nil {
 			return state.NetworkId.ValueInt64(), err
 		}
 	}
-	sourceIndex, _, err := r.client.Node.GetNodeInterface(plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString())
+	sourceIndex, _, err := r.client.Node.GetNodeInterface(ctx, plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString())
 	if err != nil {
 		return state.NetworkId.ValueInt64(), err
 	}
-	targetIndex, _, err := r.client.Node.GetNodeInterface(plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString())
+	targetIndex, _, err := r.client.Node.GetNodeInterface(ctx, plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString())
 	if err != nil {
 		return state.NetworkId.ValueInt64(), err
 	}
-	network, err := r.createOrUpdateNetwork(plan.LabPath.ValueString(), int(state.NetworkId.ValueInt64()), strconv.Itoa(int(plan.SourceNodeId.ValueInt64()))+"_"+strconv.Itoa(sourceIndex)+"_"+strconv.Itoa(int(plan.TargetNodeId.ValueInt64()))+"_"+strconv.Itoa(targetIndex))
+	network, err := r.createOrUpdateNetwork(ctx, plan.LabPath.ValueString(), int(state.NetworkId.ValueInt64()), strconv.Itoa(int(plan.SourceNodeId.ValueInt64()))+"_"+strconv.Itoa(sourceIndex)+"_"+strconv.Itoa(int(plan.TargetNodeId.ValueInt64()))+"_"+strconv.Itoa(targetIndex))
 	if err != nil {
 		return int64(network.Id), err
 	}
-	err = r.client.Node.UpdateNodeInterfaceName(plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString(), network.Id)
-	if err != nil {
-		return int64(network.Id), err
+	created := network.Id != int(state.NetworkId.ValueInt64())
+	err = r.client.Node.UpdateNodeInterfaceName(ctx, plan.LabPath.ValueString(), int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString(), network.Id)
+	if err == nil {
+		err = r.client.Node.UpdateNodeInterfaceName(ctx, plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString(), network.Id)
 	}
-	err = r.client.Node.UpdateNodeInterfaceName(plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString(), network.Id)
-	if err != nil {
-		return int64(network.Id), err
+	if err == nil {
+		network.Visibility = "0"
+		err = r.client.Network.UpdateNetwork(ctx, plan.LabPath.ValueString(), &network)
+	}
+	if err != nil && created {
+		return state.NetworkId.ValueInt64(), r.rollbackBridge(ctx, plan, network.Id, err)
 	}
-	network.Visibility = "0"
-	err = r.client.Network.UpdateNetwork(plan.LabPath.ValueString(), &network)
 	return int64(network.Id), err
 }
 
-func (r *nodeLinkResource) NewNodeLinkModelNode(state NodeLinkResourceModel) (NodeLinkResourceModel, error, bool) {
+// rollbackBridge detaches the ports of the link and deletes the bridge network created by
+// MakeNodeLinkNode when a later step failed, so that it is not left in the lab outside of the
+// Terraform state. The rollback is best effort and its outcome is added to cause.
+func (r *nodeLinkResource) rollbackBridge(ctx context.Context, plan NodeLinkResourceModel, networkId int, cause error) error {
+	// The rollback must run even when the failure was caused by a cancelled or expired context.
+	ctx = context.WithoutCancel(ctx)
+	labPath := plan.LabPath.ValueString()
+	tflog.Info(ctx, "Deleting partially created bridge network", map[string]interface{}{
+		"lab_path":   labPath,
+		"network_id": networkId,
+	})
+	var errs []error
+	if err := r.ensureInterfaceDeleted(ctx, labPath, int(plan.SourceNodeId.ValueInt64()), plan.SourcePort.ValueString(), networkId); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureInterfaceDeleted(ctx, labPath, int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString(), networkId); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.client.Network.DeleteNetwork(ctx, labPath, networkId); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		tflog.Warn(ctx, fmt.Sprintf("Failed to delete partially created bridge network: %s", errors.Join(errs...)), map[string]interface{}{
+			"lab_path":   labPath,
+			"network_id": networkId,
+		})
+		return fmt.Errorf("%w (deleting the bridge network %d failed, delete it manually before applying again: %s)", cause, networkId, errors.Join(errs...))
+	}
+	tflog.Warn(ctx, "Deleted partially created bridge network", map[string]interface{}{
+		"lab_path":   labPath,
+		"network_id": networkId,
+	})
+	return fmt.Errorf("%w (the bridge network %d was deleted)", cause, networkId)
+}
+
+func (r *nodeLinkResource) NewNodeLinkModelNode(ctx context.Context, state NodeLinkResourceModel) (NodeLinkResourceModel, error, bool) {
 	model := state
-	_, err := r.client.Network.GetNetwork(state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
+	_, err := r.client.Network.GetNetwork(ctx, state.LabPath.ValueString(), int(state.NetworkId.ValueInt64()))
 	if err != nil {
 		return model, err, true
 	}
-	_, err = r.client.Node.GetNode(state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()))
+	_, err = r.client.Node.GetNode(ctx, state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()))
 	if err != nil {
 		model.SourceNodeId = basetypes.NewInt64Value(0)
 		model.SourcePort = basetypes.NewStringValue("")
 		return model, err, false
 	}
-	_, err = r.client.Node.GetNode(state.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()))
+	_, err = r.client.Node.GetNode(ctx, state.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()))
 	if err != nil {
 		model.TargetNodeId = basetypes.NewInt64Value(0)
 		model.TargetPort = basetypes.NewStringValue("")
 		return model, err, false
 	}
-	_, sourceInt, err := r.client.Node.GetNodeInterface(state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString())
+	_, sourceInt, err := r.client.Node.GetNodeInterface(ctx, state.LabPath.ValueString(), int(state.SourceNodeId.ValueInt64()), state.SourcePort.ValueString())
 	if err != nil {
 		model.SourcePort = basetypes.NewStringValue("")
 		return model, err, false
@@ -496,7 +738,7 @@ func (r *nodeLinkResource) NewNodeLinkModelNode(state NodeLinkResourceModel) (No
 		model.SourcePort = basetypes.NewStringValue("")
 		return model, fmt.Errorf("source port %s is not connected to network %d", state.SourcePort.ValueString(), state.NetworkId.ValueInt64()), false
 	}
-	_, targetInt, err := r.client.Node.GetNodeInterface(state.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()), state.TargetPort.ValueString())
+	_, targetInt, err := r.client.Node.GetNodeInterface(ctx, state.LabPath.ValueString(), int(state.TargetNodeId.ValueInt64()), state.TargetPort.ValueString())
 	if err != nil {
 		model.SourcePort = basetypes.NewStringValue("")
 		return model, err, false
@@ -508,13 +750,13 @@ func (r *nodeLinkResource) NewNodeLinkModelNode(state NodeLinkResourceModel) (No
 	return model, nil, false
 }
 
-func (r *nodeLinkResource) ensureInterfaceDeleted(labPath string, nodeId int, port string, networkId int) error {
-	_, inter, err := r.client.Node.GetNodeInterface(labPath, nodeId, port)
+func (r *nodeLinkResource) ensureInterfaceDeleted(ctx context.Context, labPath string, nodeId int, port string, networkId int) error {
+	_, inter, err := r.client.Node.GetNodeInterface(ctx, labPath, nodeId, port)
 	if err != nil {
 		return err
 	}
 	if inter.NetworkId == networkId {
-		err = r.client.Node.UpdateNodeInterfaceName(labPath, nodeId, port, 0)
+		err = r.client.Node.UpdateNodeInterfaceName(ctx, labPath, nodeId, port, 0)
 		if err != nil {
 			return err
 		}
@@ -522,8 +764,8 @@ func (r *nodeLinkResource) ensureInterfaceDeleted(labPath string, nodeId int, po
 	return nil
 }
 
-func (r *nodeLinkResource) createOrUpdateNetwork(labPath string, networkId int, netName string) (evengsdk.Network, error) {
-	_, err := r.client.Network.GetNetwork(labPath, networkId)
+func (r *nodeLinkResource) createOrUpdateNetwork(ctx context.Context, labPath string, networkId int, netName string) (evengsdk.Network, error) {
+	_, err := r.client.Network.GetNetwork(ctx, labPath, networkId)
 	network := &evengsdk.Network{
 		Id:         networkId,
 		Left:       0,
@@ -535,10 +777,10 @@ func (r *nodeLinkResource) createOrUpdateNetwork(labPath string, networkId int,
 	}
 	if err != nil {
 		network.Id = 0
-		err = r.client.Network.CreateNetwork(labPath, network)
+		err = r.client.Network.CreateNetwork(ctx, labPath, network)
 		return *network, err
 	} else {
-		err = r.client.Network.UpdateNetwork(labPath, network)
+		err = r.client.Network.UpdateNetwork(ctx, labPath, network)
 		return *network, err
 	}
 }
@@ -548,7 +790,7 @@ func (r *nodeLinkResource) NewStyleModel(ctx context.Context, plan NodeLinkResou
 }
 
 func (r *nodeLinkResource) GetTopologyForTargetNode(ctx context.Context, plan NodeLinkResourceModel) StyleResourceModel {
-	topology, err := r.client.Lab.GetTopology(plan.LabPath.ValueString())
+	topology, err := r.client.Lab.GetTopology(ctx, plan.LabPath.ValueString())
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Failed to get topology %s", err))
 	}
@@ -626,7 +868,7 @@ func (r *nodeLinkResource) MakeNodeStyle(ctx context.Context, plan NodeLinkResou
 		Round:           json.Number(strconv.Itoa(int(plan.Style.Round.ValueInt32()))),
 		Midpoint:        plan.Style.Midpoint.ValueFloat32(),
 	}
-	err := r.client.Node.UpdateNodeInterfaceStyleByName(plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString(), style)
+	err := r.client.Node.UpdateNodeInterfaceStyleByName(ctx, plan.LabPath.ValueString(), int(plan.TargetNodeId.ValueInt64()), plan.TargetPort.ValueString(), style)
 	if err != nil {
 		tflog.Error(context.Background(), fmt.Sprintf("Failed to update node interface style %s", err))
 	}
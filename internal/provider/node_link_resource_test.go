@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccNodeLinkNetResource(t *testing.T) {
@@ -17,22 +18,29 @@ func TestAccNodeLinkNetResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccNodeLinkNetResourceConfig("e0"),
+				Config: testAccNodeLinkNetResourceConfig("Gi0/0"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node_link.test", "lab_path", "/terraform-acceptance-test-node-link.unl"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "network_id", "1"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "source_node_id", "1"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e0"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "Gi0/0"),
 				),
 			},
+			// ImportState testing
+			{
+				ResourceName:      "eveng_node_link.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccNodeLinkImportStateIdFunc("eveng_node_link.test"),
+			},
 			// Update and Read testing
 			{
-				Config: testAccNodeLinkNetResourceConfig("e1"),
+				Config: testAccNodeLinkNetResourceConfig("Gi0/1"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node_link.test", "lab_path", "/terraform-acceptance-test-node-link.unl"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "network_id", "1"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "source_node_id", "1"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e1")),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "Gi0/1")),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
@@ -46,29 +54,87 @@ func TestAccNodeLinkNodeResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccNodeLinkNodeResourceConfig("e0"),
+				Config: testAccNodeLinkNodeResourceConfig("Gi0/0"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node_link.test", "lab_path", "/terraform-acceptance-test-node-link.unl"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "network_id", "1"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e0"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "target_port", "e0"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "Gi0/0"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "target_port", "Gi0/0"),
 				),
 			},
+			// ImportState testing
+			{
+				ResourceName:      "eveng_node_link.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccNodeLinkImportStateIdFunc("eveng_node_link.test"),
+			},
 			// Update and Read testing
 			{
-				Config: testAccNodeLinkNodeResourceConfig("e1"),
+				Config: testAccNodeLinkNodeResourceConfig("Gi0/1"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node_link.test", "lab_path", "/terraform-acceptance-test-node-link.unl"),
 					resource.TestCheckResourceAttr("eveng_node_link.test", "network_id", "1"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e1"),
-					resource.TestCheckResourceAttr("eveng_node_link.test", "target_port", "e1")),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "Gi0/1"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "target_port", "Gi0/1")),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccNodeLinkNetResourceVpcs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNodeLinkNetResourceTypeConfig("e0", "vpcs"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_node_link.test", "network_id", "1"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e0"),
+				),
 			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
 
+func TestAccNodeLinkNodeResourceVpcs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNodeLinkNodeResourceTypeConfig("e0", "vpcs"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_node_link.test", "source_port", "e0"),
+					resource.TestCheckResourceAttr("eveng_node_link.test", "target_port", "e0"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccNodeLinkImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", resourceName)
+		}
+		return rs.Primary.Attributes["lab_path"] + ":" + rs.Primary.Attributes["network_id"] + ":" +
+			rs.Primary.Attributes["source_node_id"] + ":" + rs.Primary.Attributes["source_port"], nil
+	}
+}
+
 func testAccNodeLinkNetResourceConfig(configurableAttribute string) string {
+	return testAccNodeLinkNetResourceTypeConfig(configurableAttribute, "qemu")
+}
+
+func testAccNodeLinkNetResourceTypeConfig(configurableAttribute string, nodeType string) string {
 	return fmt.Sprintf(`
 resource "eveng_lab" "test" {
 	name = "terraform-acceptance-test-node-link"
@@ -87,8 +153,8 @@ resource "eveng_network" "test" {
 resource "eveng_node" "test" {
   lab_path = eveng_lab.test.path
   name = "acceptance-test-vpc"
-  template = "vpcs"
-  type = "qemu"
+  template = %[3]q
+  type = %[2]q
 }
 
 resource "eveng_node_link" "test" {
@@ -98,10 +164,14 @@ resource "eveng_node_link" "test" {
   source_port = %[1]q
 }
 
-`, configurableAttribute)
+`, configurableAttribute, nodeType, testAccNodeTemplates[nodeType])
 }
 
 func testAccNodeLinkNodeResourceConfig(configurableAttribute string) string {
+	return testAccNodeLinkNodeResourceTypeConfig(configurableAttribute, "qemu")
+}
+
+func testAccNodeLinkNodeResourceTypeConfig(configurableAttribute string, nodeType string) string {
 	return fmt.Sprintf(`
 resource "eveng_lab" "test" {
 	name = "terraform-acceptance-test-node-link"
@@ -114,8 +184,8 @@ resource "eveng_node" "test" {
   count = 2
   lab_path = eveng_lab.test.path
   name = "acceptance-test-vpc"
-  template = "vpcs"
-  type = "qemu"
+  template = %[3]q
+  type = %[2]q
 }
 
 resource "eveng_node_link" "test" {
@@ -126,5 +196,43 @@ resource "eveng_node_link" "test" {
   target_port = %[1]q
 }
 
-`, configurableAttribute)
+`, configurableAttribute, nodeType, testAccNodeTemplates[nodeType])
+}
+
+func TestParseNodeLinkImportId(t *testing.T) {
+	for _, test := range []struct {
+		id        string
+		labPath   string
+		networkId int64
+		source    *linkEndpoint
+		err       bool
+	}{
+		{id: "/labs/core.unl:4", labPath: "/labs/core.unl", networkId: 4},
+		{id: "/labs/core.unl:4:1:Gi0/1", labPath: "/labs/core.unl", networkId: 4, source: &linkEndpoint{NodeId: 1, Port: "Gi0/1"}},
+		{id: "/labs/a:b/core.unl:4", labPath: "/labs/a:b/core.unl", networkId: 4},
+		{id: "/labs/a:b/core.unl:4:1:e0", labPath: "/labs/a:b/core.unl", networkId: 4, source: &linkEndpoint{NodeId: 1, Port: "e0"}},
+		{id: "/labs/core.unl", err: true},
+		{id: "/labs/core.unl:", err: true},
+		{id: "/labs/core:4", err: true},
+		{id: "/labs/core.unl:net", err: true},
+		{id: "/labs/core.unl:4:0:e0", err: true},
+	} {
+		labPath, networkId, source, err := parseNodeLinkImportId(test.id)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.id, err)
+			continue
+		}
+		if labPath != test.labPath || networkId != test.networkId {
+			t.Errorf("%q: expected %s and %d, got %s and %d", test.id, test.labPath, test.networkId, labPath, networkId)
+		}
+		if (source == nil) != (test.source == nil) || (source != nil && *source != *test.source) {
+			t.Errorf("%q: expected source %v, got %v", test.id, test.source, source)
+		}
+	}
 }
@@ -5,20 +5,49 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"maps"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 )
 
+const (
+	nodeStateStarted = "started"
+	nodeStateStopped = "stopped"
+)
+
+// nodeTypes are the node types supported by EVE-NG.
+var nodeTypes = []string{"qemu", "iol", "dynamips", "docker", "vpcs"}
+
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &nodeResource{}
-	_ resource.ResourceWithConfigure = &nodeResource{}
+	_ resource.Resource                   = &nodeResource{}
+	_ resource.ResourceWithConfigure      = &nodeResource{}
+	_ resource.ResourceWithImportState    = &nodeResource{}
+	_ resource.ResourceWithModifyPlan     = &nodeResource{}
+	_ resource.ResourceWithValidateConfig = &nodeResource{}
 )
 
 // NewNodeResource is a helper function to simplify the provider implementation.
@@ -28,29 +57,60 @@ func NewNodeResource() resource.Resource {
 
 // nodeResource is the resource implementation.
 type nodeResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 // nodeResourceModel describes the resource data model.
 type nodeResourceModel struct {
-	LabPath    types.String `tfsdk:"lab_path"`
-	Console    types.String `tfsdk:"console"`
-	Delay      types.Int64  `tfsdk:"delay"`
-	Id         types.Int64  `tfsdk:"id"`
-	Left       types.Int64  `tfsdk:"left"`
-	Icon       types.String `tfsdk:"icon"`
-	Image      types.String `tfsdk:"image"`
-	Name       types.String `tfsdk:"name"`
-	Ram        types.Int64  `tfsdk:"ram"`
-	Template   types.String `tfsdk:"template"`
-	Type       types.String `tfsdk:"type"`
-	Top        types.Int64  `tfsdk:"top"`
-	Url        types.String `tfsdk:"url"`
-	Config     types.String `tfsdk:"config"`
-	Cpu        types.Int64  `tfsdk:"cpu"`
-	Ethernet   types.Int64  `tfsdk:"ethernet"`
-	Interfaces types.Object `tfsdk:"interfaces"`
-	Uuid       types.String `tfsdk:"uuid"`
+	LabPath    types.String    `tfsdk:"lab_path"`
+	Console    types.String    `tfsdk:"console"`
+	Delay      types.Int64     `tfsdk:"delay"`
+	Id         types.Int64     `tfsdk:"id"`
+	Left       types.Int64     `tfsdk:"left"`
+	Icon       types.String    `tfsdk:"icon"`
+	Image      types.String    `tfsdk:"image"`
+	Name       types.String    `tfsdk:"name"`
+	Ram        types.Int64     `tfsdk:"ram"`
+	Template   types.String    `tfsdk:"template"`
+	Type       types.String    `tfsdk:"type"`
+	Top        types.Int64     `tfsdk:"top"`
+	Url        types.String    `tfsdk:"url"`
+	Config     nodeConfigValue `tfsdk:"config"`
+	Cpu        types.Int64     `tfsdk:"cpu"`
+	Ethernet   types.Int64     `tfsdk:"ethernet"`
+	Interfaces types.Object    `tfsdk:"interfaces"`
+	Uuid       types.String    `tfsdk:"uuid"`
+	State      types.String    `tfsdk:"state"`
+
+	ConfigSha256  types.String `tfsdk:"config_sha256"`
+	ConfigEnabled types.Bool   `tfsdk:"config_enabled"`
+
+	ConfigFile     types.String    `tfsdk:"config_file"`
+	ConfigVars     types.Map       `tfsdk:"config_vars"`
+	ConfigRendered nodeConfigValue `tfsdk:"config_rendered"`
+
+	DestroyExportFile types.String `tfsdk:"destroy_export_file"`
+
+	WipeOnConfigChange types.Bool   `tfsdk:"wipe_on_config_change"`
+	WipeTrigger        types.String `tfsdk:"wipe_trigger"`
+
+	QemuOptions types.String `tfsdk:"qemu_options"`
+	QemuVersion types.String `tfsdk:"qemu_version"`
+	QemuArch    types.String `tfsdk:"qemu_arch"`
+	QemuNic     types.String `tfsdk:"qemu_nic"`
+	Cpulimit    types.Bool   `tfsdk:"cpulimit"`
+	Firstmac    types.String `tfsdk:"firstmac"`
+
+	Serial types.Int64  `tfsdk:"serial"`
+	Nvram  types.Int64  `tfsdk:"nvram"`
+	Slots  types.Map    `tfsdk:"slots"`
+	Idlepc types.String `tfsdk:"idlepc"`
+
+	DockerTag     types.String `tfsdk:"docker_tag"`
+	DockerEnv     types.Map    `tfsdk:"docker_env"`
+	DockerArgs    types.String `tfsdk:"docker_args"`
+	DockerIp      types.String `tfsdk:"docker_ip"`
+	DockerGateway types.String `tfsdk:"docker_gateway"`
 }
 
 type interfacesResourceModel struct {
@@ -71,11 +131,11 @@ func (r *nodeResource) Configure(_ context.Context, req resource.ConfigureReques
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -91,19 +151,32 @@ func (r *nodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"lab_path": schema.StringAttribute{
 				Required:    true,
 				Description: "Path to the lab file.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"console": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "Console type of the node.",
+				Description: "Console type of the node, such as telnet, vnc or rdp. It must be one of the consoles the template allows. Defaults to the console of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"delay": schema.Int64Attribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Delay in milliseconds.",
+				Description: "Seconds EVE-NG waits before starting the node when the whole lab is started.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"id": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Unique Id of the node.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
 			"left": schema.Int64Attribute{
 				Optional:    true,
@@ -118,7 +191,11 @@ func (r *nodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"image": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "Image associated with the node.",
+				Description: "Image associated with the node. It must be one of the images installed for the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -131,11 +208,20 @@ func (r *nodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			},
 			"template": schema.StringAttribute{
 				Required:    true,
-				Description: "Template used for the node.",
+				Description: "Template used for the node. It must exist on the server and have an image installed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"type": schema.StringAttribute{
 				Required:    true,
-				Description: "Type of the node.",
+				Description: "Type of the node, one of " + strings.Join(nodeTypes, ", ") + ". It must be one of the types the template allows.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nodeTypes...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"top": schema.Int64Attribute{
 				Optional:    true,
@@ -145,10 +231,56 @@ func (r *nodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"url": schema.StringAttribute{
 				Computed:    true,
 				Description: "URL associated with the node.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"config": schema.StringAttribute{
 				Optional:    true,
-				Description: "Startup configuration of the node.",
+				CustomType:  nodeConfigType{},
+				Description: "Startup configuration of the node. Differences in line endings, trailing whitespace and trailing empty lines are ignored. When neither config nor config_file is set, the configuration of an existing node is left as is.",
+			},
+			"config_file": schema.StringAttribute{
+				Optional: true,
+				Description: "Path on the Terraform host of a Go text/template rendered into the startup configuration of the node. " +
+					"The template is given the node as .Name, .Id, .Interfaces.Ethernet and .Interfaces.Serial, and config_vars as .Vars.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("config")),
+				},
+			},
+			"config_vars": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Variables config_file is rendered with.",
+				Validators: []validator.Map{
+					mapvalidator.AlsoRequires(path.MatchRoot("config_file")),
+				},
+			},
+			"destroy_export_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path on the Terraform host where the configuration of the node is saved before it is destroyed, its running configuration being exported first when the node is started.",
+			},
+			"wipe_on_config_change": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether the node is wiped when its startup configuration changes, so that it boots from the new configuration. A started node is stopped for the wipe and started again, unless state is \"stopped\".",
+			},
+			"wipe_trigger": schema.StringAttribute{
+				Optional:    true,
+				Description: "Arbitrary value whose changes wipe the node the way wipe_on_config_change does. Setting it for the first time or removing it does not wipe the node.",
+			},
+			"config_rendered": schema.StringAttribute{
+				Computed:    true,
+				CustomType:  nodeConfigType{},
+				Description: "Startup configuration of the node as stored on the server, config or config_file rendered.",
+			},
+			"config_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 of the startup configuration stored on the server, normalized the way config is compared.",
+			},
+			"config_enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the node boots with its startup configuration. Defaults to true, or to the current setting of an existing node when neither config nor config_file is set.",
 			},
 			"cpu": schema.Int64Attribute{
 				Optional:    true,
@@ -177,13 +309,332 @@ func (r *nodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			"uuid": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "UUID of the node. Set it for images licensed against a UUID, otherwise EVE-NG generates one for QEMU nodes.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"qemu_options": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Extra QEMU command line options. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"qemu_version": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "UUID of the node.",
+				Description: "QEMU version used to run the node. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"qemu_arch": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "QEMU architecture of the node (e.g. x86_64). Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"qemu_nic": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "QEMU model of the network interfaces (e.g. e1000, virtio-net-pci). Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cpulimit": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the CPU usage of the node is limited. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"firstmac": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "MAC address of the first interface of the node, the next interfaces using the following addresses.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"serial": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Number of serial port groups of an IOL node, each holding 4 serial interfaces. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"nvram": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "NVRAM in KB of an IOL or Dynamips node. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"slots": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Modules of a Dynamips node keyed by slot number (e.g. { \"1\" = \"NM-4T\" }). Defaults to the modules of the template.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"idlepc": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Idle-PC value of a Dynamips node. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"docker_tag": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Tag of the container image of a docker node. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"docker_env": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Environment variables of the container of a docker node.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"docker_args": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Extra arguments passed to docker run for a docker node. Defaults to the value of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"docker_ip": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Management IP address of the container of a docker node, in CIDR notation (e.g. 10.0.0.10/24).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"docker_gateway": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Management gateway of the container of a docker node.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"state": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(nodeStateStarted, nodeStateStopped),
+				},
+				Description: "Power state of the node, either \"started\" or \"stopped\". When unset the node is left as is.",
 			},
 		},
 	}
 }
 
+// nodeTypeAttributes are the attributes that only apply to some node types.
+var nodeTypeAttributes = []struct {
+	name  string
+	types []string
+}{
+	{name: "serial", types: []string{"iol"}},
+	{name: "nvram", types: []string{"iol", "dynamips"}},
+	{name: "slots", types: []string{"dynamips"}},
+	{name: "idlepc", types: []string{"dynamips"}},
+	{name: "docker_tag", types: []string{"docker"}},
+	{name: "docker_env", types: []string{"docker"}},
+	{name: "docker_args", types: []string{"docker"}},
+	{name: "docker_ip", types: []string{"docker"}},
+	{name: "docker_gateway", types: []string{"docker"}},
+}
+
+// ValidateConfig rejects the attributes that do not apply to the type of the node.
+func (r *nodeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config nodeResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for slot := range config.Slots.Elements() {
+		if _, ok := slotNumber(slotPrefix + slot); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("slots").AtMapKey(slot),
+				"Invalid slot number",
+				fmt.Sprintf("Slots must be keyed by slot number, got %q.", slot),
+			)
+		}
+	}
+
+	if !config.DockerIp.IsUnknown() && !config.DockerIp.IsNull() {
+		if _, err := netip.ParsePrefix(config.DockerIp.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("docker_ip"), "Invalid IP address", err.Error())
+		}
+	}
+	if !config.DockerGateway.IsUnknown() && !config.DockerGateway.IsNull() {
+		if _, err := netip.ParseAddr(config.DockerGateway.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("docker_gateway"), "Invalid IP address", err.Error())
+		}
+	}
+
+	if config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+	nodeType := config.Type.ValueString()
+	values := map[string]attr.Value{
+		"serial":         config.Serial,
+		"nvram":          config.Nvram,
+		"slots":          config.Slots,
+		"idlepc":         config.Idlepc,
+		"docker_tag":     config.DockerTag,
+		"docker_env":     config.DockerEnv,
+		"docker_args":    config.DockerArgs,
+		"docker_ip":      config.DockerIp,
+		"docker_gateway": config.DockerGateway,
+	}
+	for _, attribute := range nodeTypeAttributes {
+		if values[attribute.name].IsNull() || slices.Contains(attribute.types, nodeType) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root(attribute.name),
+			"Attribute not supported by the node type",
+			fmt.Sprintf("%s only applies to nodes of type %s, got %q.", attribute.name, strings.Join(attribute.types, " or "), nodeType),
+		)
+	}
+}
+
+// ModifyPlan checks the template, type and image of the node against the server, so that
+// mistakes are reported at plan time instead of during Create.
+func (r *nodeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the resource is destroyed or the provider is not configured yet.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var plan nodeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The prior state of the node, nil when the node is created.
+	var prior *nodeResourceModel
+	if !req.State.Raw.IsNull() {
+		prior = &nodeResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The configuration is stored as planned, so it and its hash are known before the apply
+	// whenever it can be rendered.
+	config, known, err := r.plannedConfig(ctx, prior, plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("config_file"), "Failed to render node config", err.Error())
+		return
+	}
+	if known {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("config_rendered"), newNodeConfigValue(config))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("config_sha256"), nodeConfigSha256(config))...)
+	}
+	if plan.ConfigEnabled.IsUnknown() && !plan.Config.IsUnknown() && !plan.ConfigFile.IsUnknown() {
+		enabled := types.BoolValue(true)
+		// The configuration of an existing node is left as is when Terraform does not manage it.
+		if prior != nil && !hasNodeConfigSource(plan) {
+			enabled = prior.ConfigEnabled
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("config_enabled"), enabled)...)
+	}
+	if resp.Diagnostics.HasError() || plan.Template.IsUnknown() {
+		return
+	}
+
+	// The template, type and image of an existing node are only checked again when they change,
+	// so that a template or image removed from the server later does not fail the plans of the
+	// nodes created from it.
+	if prior != nil && plan.Template.Equal(prior.Template) && plan.Type.Equal(prior.Type) && plan.Image.Equal(prior.Image) && plan.Console.Equal(prior.Console) {
+		return
+	}
+
+	name := plan.Template.ValueString()
+	templates, err := r.client.Node.GetTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to validate node template", fmt.Sprintf("Failed to list templates: %s", err))
+		return
+	}
+	description, ok := templates[name]
+	if !ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("template"),
+			"Unknown node template",
+			fmt.Sprintf("Template %q does not exist on the server. Use the eveng_templates data source to list the available templates.", name),
+		)
+		return
+	}
+	if _, installed := splitTemplateDescription(description); !installed {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("template"),
+			"Node template without image",
+			fmt.Sprintf("No image is installed for template %q.", name),
+		)
+		return
+	}
+
+	tmpl, err := getNodeTemplate(ctx, r.client, name)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to validate node template", err.Error())
+		return
+	}
+	if types := tmpl.Types(); !plan.Type.IsUnknown() && len(types) > 0 && !slices.Contains(types, plan.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Node type does not match the template",
+			fmt.Sprintf("Template %q allows nodes of type %s, got %q.", name, strings.Join(types, ", "), plan.Type.ValueString()),
+		)
+	}
+	if consoles := tmpl.Options["console"].List; !plan.Console.IsUnknown() && !plan.Console.IsNull() && len(consoles) > 0 && !slices.Contains(consoles, plan.Console.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("console"),
+			"Console not allowed by the template",
+			fmt.Sprintf("Template %q allows the consoles %s, got %q.", name, strings.Join(consoles, ", "), plan.Console.ValueString()),
+		)
+	}
+	images := tmpl.Images()
+	if plan.Image.IsUnknown() || plan.Image.IsNull() || len(images) == 0 {
+		return
+	}
+	for _, image := range images {
+		if image == plan.Image.ValueString() {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(
+		path.Root("image"),
+		"Image not installed",
+		fmt.Sprintf("Image %q is not installed for template %q. Installed images: %s.", plan.Image.ValueString(), name, strings.Join(images, ", ")),
+	)
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *nodeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan nodeResourceModel
@@ -193,43 +644,73 @@ func (r *nodeResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	node, err := r.NewNode(plan)
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	node, err := r.NewNode(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create node", err.Error())
 		return
 	}
-	err = r.client.Node.CreateNode(plan.LabPath.ValueString(), &node)
+	err = r.client.Node.CreateNodeDetails(ctx, plan.LabPath.ValueString(), &node)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create node", err.Error())
 		return
 	}
 	tflog.Info(ctx, fmt.Sprintf("Created node %d", node.Id))
-	_, err = r.client.Node.GetNodeConfig(plan.LabPath.ValueString(), node.Id)
+	_, err = r.client.Node.GetNodeConfig(ctx, plan.LabPath.ValueString(), node.Id)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node config", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
+		return
+	}
+	config, err := r.nodeConfig(ctx, plan, node.Id)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render node config", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
 		return
 	}
-	err = r.client.Node.UpdateNodeConfig(plan.LabPath.ValueString(), node.Id, plan.Config.ValueString())
+	err = r.client.Node.UpdateNodeConfig(ctx, plan.LabPath.ValueString(), node.Id, config)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update node config", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
 		return
 	}
-	node.Config = "1"
-	err = r.client.Node.UpdateNode(plan.LabPath.ValueString(), &node)
+	node.Config = nodeConfigEnabled(plan.ConfigEnabled)
+	err = r.client.Node.UpdateNodeDetails(ctx, plan.LabPath.ValueString(), &node)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update node config", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
 		return
 	}
-	ints, err := r.NewInterfaceModel(plan.LabPath.ValueString(), node.Id)
+	if plan.State.ValueString() == nodeStateStarted {
+		err = r.SetNodeState(ctx, plan.LabPath.ValueString(), node.Id, nodeStateStarted)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to start node", err.Error())
+			r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
+			return
+		}
+	}
+	ints, err := r.NewInterfaceModel(ctx, plan.LabPath.ValueString(), node.Id)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node interfaces", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
 		return
 	}
-	state, err := r.NewNodeModel(plan.LabPath.ValueString(), node.Id)
+	state, err := r.NewNodeModel(ctx, plan.LabPath.ValueString(), node.Id)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node", err.Error())
+		r.rollbackCreate(ctx, &resp.Diagnostics, plan.LabPath.ValueString(), node.Id)
 		return
 	}
+	if !plan.State.IsUnknown() {
+		state.State = plan.State
+	}
+	state.setConfigSource(plan)
 	objectValue, diags := types.ObjectValueFrom(ctx, ints.AttributeTypes(), ints)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -243,6 +724,38 @@ func (r *nodeResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 }
 
+// rollbackCreate deletes a node whose creation failed halfway, so that it is not left in the lab
+// outside of the Terraform state. The deletion is best effort: when it fails, a warning asks to
+// delete the node manually.
+func (r *nodeResource) rollbackCreate(ctx context.Context, diags *diag.Diagnostics, labPath string, nodeId int) {
+	// The rollback must run even when the failure was caused by a cancelled or expired context.
+	ctx = context.WithoutCancel(ctx)
+	tflog.Info(ctx, "Deleting partially created node", map[string]interface{}{
+		"lab_path": labPath,
+		"node_id":  nodeId,
+	})
+	err := r.client.Node.StopNode(ctx, labPath, nodeId)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Failed to stop node: %s", err), map[string]interface{}{
+			"lab_path": labPath,
+			"node_id":  nodeId,
+		})
+	}
+	err = r.client.Node.DeleteNode(ctx, labPath, nodeId)
+	if err != nil {
+		diags.AddWarning(
+			"Failed to delete partially created node",
+			fmt.Sprintf("Node %d was created in lab %s but could not be configured, and deleting it failed: %s. "+
+				"Delete the node manually before applying again.", nodeId, labPath, err),
+		)
+		return
+	}
+	diags.AddWarning(
+		"Deleted partially created node",
+		fmt.Sprintf("Node %d was created in lab %s but could not be configured, so it was deleted.", nodeId, labPath),
+	)
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (r *nodeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state nodeResourceModel
@@ -252,12 +765,14 @@ func (r *nodeResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	state, err := r.NewNodeModel(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	prior := state
+	state, err := r.NewNodeModel(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
-	ints, err := r.NewInterfaceModel(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	state.setConfigSource(prior)
+	ints, err := r.NewInterfaceModel(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node interfaces", err.Error())
 		return
@@ -291,29 +806,65 @@ func (r *nodeResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	node, err := r.NewNode(plan)
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create node", err.Error())
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
 		return
 	}
-	node.Id = int(state.Id.ValueInt64())
-	node.Config = "1"
-	err = r.client.Node.UpdateNodeConfig(plan.LabPath.ValueString(), node.Id, plan.Config.ValueString())
+	defer unlock()
+
+	node, err := r.NewNode(ctx, plan)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to update node config", err.Error())
+		resp.Diagnostics.AddError("Failed to create node", err.Error())
 		return
 	}
-	err = r.client.Node.UpdateNode(plan.LabPath.ValueString(), &node)
+	node.Id = int(state.Id.ValueInt64())
+	node.Config = nodeConfigEnabled(plan.ConfigEnabled)
+	err = r.client.Node.UpdateNodeDetails(ctx, plan.LabPath.ValueString(), &node)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to update node", err.Error())
 		return
 	}
-	state, err = r.NewNodeModel(plan.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	// The configuration is rendered once the node is updated, with its new interfaces. It is
+	// left as is when neither config nor config_file is set, such as after an import.
+	config := state.ConfigRendered.ValueString()
+	if hasNodeConfigSource(plan) {
+		config, err = r.nodeConfig(ctx, plan, node.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to render node config", err.Error())
+			return
+		}
+		err = r.client.Node.UpdateNodeConfig(ctx, plan.LabPath.ValueString(), node.Id, config)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update node config", err.Error())
+			return
+		}
+	}
+	configChanged := normalizeNodeConfig(config) != normalizeNodeConfig(state.ConfigRendered.ValueString())
+	if (plan.WipeOnConfigChange.ValueBool() && configChanged) || wipeTriggerChanged(state.WipeTrigger, plan.WipeTrigger) {
+		err = r.wipe(ctx, plan.LabPath.ValueString(), node.Id, plan.State.ValueString() != nodeStateStopped)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to wipe node", err.Error())
+			return
+		}
+	}
+	if !plan.State.IsUnknown() && !plan.State.Equal(state.State) {
+		err = r.SetNodeState(ctx, plan.LabPath.ValueString(), node.Id, plan.State.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to change node state", err.Error())
+			return
+		}
+	}
+	state, err = r.NewNodeModel(ctx, plan.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node", err.Error())
 		return
 	}
-	ints, err := r.NewInterfaceModel(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	if !plan.State.IsUnknown() {
+		state.State = plan.State
+	}
+	state.setConfigSource(plan)
+	ints, err := r.NewInterfaceModel(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to get node interfaces", err.Error())
 		return
@@ -340,19 +891,163 @@ func (r *nodeResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	err := r.client.Node.DeleteNode(state.LabPath.ValueString(), int(state.Id.ValueInt64()))
+	unlock, err := r.client.LockLab(ctx, state.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	if !state.DestroyExportFile.IsNull() {
+		err = r.exportConfig(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()), state.DestroyExportFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("destroy_export_file"), "Failed to export node config", err.Error())
+			return
+		}
+	}
+
+	err = r.client.Node.DeleteNode(ctx, state.LabPath.ValueString(), int(state.Id.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete node", err.Error())
 		return
 	}
 }
 
-func (r *nodeResource) NewNode(model nodeResourceModel) (evengsdk.Node, error) {
-	tmpl, err := r.client.Node.GetTemplate(model.Template.ValueString())
+// wipe wipes the node. A started node is stopped for the wipe, and started again when restart
+// is true.
+func (r *nodeResource) wipe(ctx context.Context, labPath string, nodeId int, restart bool) error {
+	node, err := r.client.Node.GetNode(ctx, labPath, nodeId)
+	if err != nil {
+		return err
+	}
+	running := isNodeRunning(node.Status)
+	tflog.Info(ctx, "Wiping node", map[string]interface{}{
+		"lab_path": labPath,
+		"node_id":  nodeId,
+		"running":  running,
+	})
+	if running {
+		err = r.client.Node.StopNode(ctx, labPath, nodeId)
+		if err != nil {
+			return err
+		}
+	}
+	err = r.client.Node.WipeNode(ctx, labPath, nodeId)
 	if err != nil {
-		return evengsdk.Node{}, err
+		return err
+	}
+	if running && restart {
+		return r.client.Node.StartNode(ctx, labPath, nodeId)
+	}
+	return nil
+}
+
+// exportConfig saves the configuration of the node to file, exporting its running configuration
+// first when the node is started.
+func (r *nodeResource) exportConfig(ctx context.Context, labPath string, nodeId int, file string) error {
+	node, err := r.client.Node.GetNode(ctx, labPath, nodeId)
+	if err != nil {
+		return err
+	}
+	if isNodeRunning(node.Status) {
+		err = r.client.Node.ExportNodeConfig(ctx, labPath, nodeId)
+		if err != nil {
+			return err
+		}
+	}
+	config, err := r.client.Node.GetNodeConfig(ctx, labPath, nodeId)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
 	}
-	node := evengsdk.Node{}
+	return os.WriteFile(file, []byte(config), 0o600)
+}
+
+// ImportState imports an existing node using an identifier of the form "lab_path:node_id".
+func (r *nodeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	labPath, nodeId, err := parseNodeImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("id"), "Invalid import identifier", err.Error())
+		return
+	}
+
+	state, err := r.NewNodeModel(ctx, labPath, nodeId)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to import node", fmt.Sprintf("Unable to read node %d in lab %s: %s", nodeId, labPath, err))
+		return
+	}
+	ints, err := r.NewInterfaceModel(ctx, labPath, nodeId)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get node interfaces", err.Error())
+		return
+	}
+	objectValue, diags := types.ObjectValueFrom(ctx, ints.AttributeTypes(), ints)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Interfaces = objectValue
+	tflog.Info(ctx, "Imported node", map[string]interface{}{
+		"lab_path": labPath,
+		"node_id":  nodeId,
+	})
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// splitImportId splits an import identifier into a lab path and the given number of fields
+// following it, all separated by colons. The fields are split from the end, so that the lab
+// path may contain colons.
+func splitImportId(id string, fields int) (string, []string, bool) {
+	parts := make([]string, fields)
+	for i := fields - 1; i >= 0; i-- {
+		sep := strings.LastIndex(id, ":")
+		if sep <= 0 || sep == len(id)-1 {
+			return "", nil, false
+		}
+		parts[i] = id[sep+1:]
+		id = id[:sep]
+	}
+	return id, parts, true
+}
+
+// parseNodeImportId splits an import identifier of the form "lab_path:node_id".
+func parseNodeImportId(id string) (string, int, error) {
+	labPath, parts, ok := splitImportId(id, 1)
+	if !ok {
+		return "", 0, fmt.Errorf("expected import identifier with format \"lab_path:node_id\", got %q", id)
+	}
+	if !strings.HasSuffix(labPath, ".unl") {
+		return "", 0, fmt.Errorf("lab path %q must point to a .unl file", labPath)
+	}
+	nodeId, err := strconv.Atoi(parts[0])
+	if err != nil || nodeId <= 0 {
+		return "", 0, fmt.Errorf("node id %q must be a positive integer", parts[0])
+	}
+	return labPath, nodeId, nil
+}
+
+// SetNodeState starts or stops the node.
+func (r *nodeResource) SetNodeState(ctx context.Context, labPath string, nodeId int, nodeState string) error {
+	tflog.Info(ctx, "Changing node state", map[string]interface{}{
+		"lab_path": labPath,
+		"node_id":  nodeId,
+		"state":    nodeState,
+	})
+	if nodeState == nodeStateStopped {
+		return r.client.Node.StopNode(ctx, labPath, nodeId)
+	}
+	return r.client.Node.StartNode(ctx, labPath, nodeId)
+}
+
+func (r *nodeResource) NewNode(ctx context.Context, model nodeResourceModel) (nodeDetails, error) {
+	tmpl, err := getNodeTemplate(ctx, r.client, model.Template.ValueString())
+	if err != nil {
+		return nodeDetails{}, err
+	}
+	node := nodeDetails{}
 	if !model.Console.IsUnknown() {
 		node.Console = model.Console.ValueString()
 	}
@@ -365,9 +1060,7 @@ func (r *nodeResource) NewNode(model nodeResourceModel) (evengsdk.Node, error) {
 	if !model.Icon.IsUnknown() {
 		node.Icon = model.Icon.ValueString()
 	} else {
-		if icon, ok := tmpl["options"].(map[string]interface{})["icon"].(map[string]interface{})["value"].(string); ok {
-			node.Icon = icon
-		}
+		node.Icon = tmpl.Value("icon")
 	}
 	if !model.Image.IsUnknown() {
 		node.Image = model.Image.ValueString()
@@ -390,21 +1083,178 @@ func (r *nodeResource) NewNode(model nodeResourceModel) (evengsdk.Node, error) {
 	}
 	if !model.Ethernet.IsUnknown() {
 		node.Ethernet = int(model.Ethernet.ValueInt64())
+	} else if ethernet := tmpl.Int64Value("ethernet"); ethernet != nil {
+		node.Ethernet = int(*ethernet)
+	}
+	if !model.Uuid.IsUnknown() {
+		node.Uuid = model.Uuid.ValueString()
+	}
+	node.QemuOptions = apiString(stringOrTemplate(model.QemuOptions, tmpl, "qemu_options"))
+	node.QemuVersion = apiString(stringOrTemplate(model.QemuVersion, tmpl, "qemu_version"))
+	node.QemuArch = apiString(stringOrTemplate(model.QemuArch, tmpl, "qemu_arch"))
+	node.QemuNic = apiString(stringOrTemplate(model.QemuNic, tmpl, "qemu_nic"))
+	node.Firstmac = apiString(stringOrTemplate(model.Firstmac, tmpl, "firstmac"))
+	if !model.Cpulimit.IsUnknown() {
+		node.Cpulimit = "0"
+		if model.Cpulimit.ValueBool() {
+			node.Cpulimit = "1"
+		}
 	} else {
-		// Check if the template has an ethernet option
-		_, ok := tmpl["options"].(map[string]interface{})["ethernet"]
-
-		if ok {
-			if ethernet, ok := tmpl["options"].(map[string]interface{})["ethernet"].(map[string]interface{})["value"].(float64); ok {
-				node.Ethernet = int(ethernet)
+		node.Cpulimit = apiString(tmpl.Value("cpulimit"))
+	}
+	node.Serial = apiString(int64OrTemplate(model.Serial, tmpl, "serial"))
+	node.Nvram = apiString(int64OrTemplate(model.Nvram, tmpl, "nvram"))
+	node.Idlepc = apiString(stringOrTemplate(model.Idlepc, tmpl, "idlepc"))
+	for key := range tmpl.Options {
+		if slot, ok := slotNumber(key); ok {
+			if node.Slots == nil {
+				node.Slots = make(map[string]string)
 			}
+			node.Slots[slot] = tmpl.Value(key)
+		}
+	}
+	if !model.Slots.IsUnknown() && !model.Slots.IsNull() {
+		slots := make(map[string]string)
+		diags := model.Slots.ElementsAs(ctx, &slots, false)
+		if diags.HasError() {
+			return nodeDetails{}, fmt.Errorf("failed to read slots: %v", diags)
+		}
+		// Empty the slots of the template the configuration leaves out.
+		for slot := range node.Slots {
+			node.Slots[slot] = ""
+		}
+		if node.Slots == nil {
+			node.Slots = make(map[string]string)
+		}
+		for slot, module := range slots {
+			node.Slots[slot] = module
+		}
+	}
+	node.DockerTag = apiString(stringOrTemplate(model.DockerTag, tmpl, "docker_tag"))
+	node.DockerArgs = apiString(stringOrTemplate(model.DockerArgs, tmpl, "docker_args"))
+	node.DockerIp = apiString(stringOrTemplate(model.DockerIp, tmpl, "docker_ip"))
+	node.DockerGateway = apiString(stringOrTemplate(model.DockerGateway, tmpl, "docker_gateway"))
+	node.DockerEnv = apiString(tmpl.Value("docker_env"))
+	if !model.DockerEnv.IsUnknown() && !model.DockerEnv.IsNull() {
+		env := make(map[string]string)
+		diags := model.DockerEnv.ElementsAs(ctx, &env, false)
+		if diags.HasError() {
+			return nodeDetails{}, fmt.Errorf("failed to read docker_env: %v", diags)
 		}
+		node.DockerEnv = apiString(formatDockerEnv(env))
 	}
 	return node, nil
 }
 
-func (r *nodeResource) NewNodeModel(labPath string, nodeId int) (nodeResourceModel, error) {
-	node, err := r.client.Node.GetNode(labPath, nodeId)
+// int64OrTemplate is stringOrTemplate for numeric attributes.
+func int64OrTemplate(value types.Int64, tmpl *nodeTemplate, key string) string {
+	if !value.IsUnknown() && !value.IsNull() {
+		return strconv.FormatInt(value.ValueInt64(), 10)
+	}
+	return tmpl.Value(key)
+}
+
+// nodeConfig returns the startup configuration of the node, config or config_file rendered
+// with the current interfaces of the node.
+func (r *nodeResource) nodeConfig(ctx context.Context, plan nodeResourceModel, nodeId int) (string, error) {
+	if plan.ConfigFile.IsNull() {
+		return plan.Config.ValueString(), nil
+	}
+	ints, err := r.NewInterfaceModel(ctx, plan.LabPath.ValueString(), nodeId)
+	if err != nil {
+		return "", err
+	}
+	return renderNodeConfigFile(ctx, plan, int64(nodeId), ints)
+}
+
+// plannedConfig returns the startup configuration the plan results in, when it is known
+// before the apply. A config_file can only be rendered for an existing node whose interfaces
+// do not change, but it is parsed in any case so that template errors show up in the plan.
+func (r *nodeResource) plannedConfig(ctx context.Context, prior *nodeResourceModel, plan nodeResourceModel) (string, bool, error) {
+	if plan.Config.IsUnknown() || plan.ConfigFile.IsUnknown() {
+		return "", false, nil
+	}
+	if prior != nil && !hasNodeConfigSource(plan) {
+		return prior.ConfigRendered.ValueString(), true, nil
+	}
+	if plan.ConfigFile.IsNull() {
+		return plan.Config.ValueString(), true, nil
+	}
+	if _, err := parseNodeConfigFile(plan.ConfigFile.ValueString()); err != nil {
+		return "", false, err
+	}
+	if prior == nil || plan.Name.IsUnknown() || plan.ConfigVars.IsUnknown() || plan.Id.IsUnknown() {
+		return "", false, nil
+	}
+	if !plan.Ethernet.Equal(prior.Ethernet) || !plan.Serial.Equal(prior.Serial) || !plan.Slots.Equal(prior.Slots) || prior.Interfaces.IsNull() {
+		return "", false, nil
+	}
+	ints, err := interfacesFromObject(ctx, prior.Interfaces)
+	if err != nil {
+		return "", false, err
+	}
+	config, err := renderNodeConfigFile(ctx, plan, plan.Id.ValueInt64(), ints)
+	if err != nil {
+		return "", false, err
+	}
+	return config, true, nil
+}
+
+// setConfigSource carries the attributes that only live in Terraform, such as config_file, over
+// from the plan or the prior state to a model read from the server. When config is not set,
+// the configuration of the server only goes to config_rendered, config being left null as
+// configured.
+func (m *nodeResourceModel) setConfigSource(source nodeResourceModel) {
+	m.ConfigFile = source.ConfigFile
+	m.DestroyExportFile = source.DestroyExportFile
+	m.WipeOnConfigChange = source.WipeOnConfigChange
+	m.WipeTrigger = source.WipeTrigger
+	m.ConfigVars = source.ConfigVars
+	if m.ConfigVars.IsNull() {
+		m.ConfigVars = types.MapNull(types.StringType)
+	}
+	if source.Config.IsNull() {
+		m.Config = newNodeConfigNull()
+		return
+	}
+	m.Config = keepEmptyNodeConfig(source.Config, m.Config)
+}
+
+// wipeTriggerChanged tells whether wipe_trigger changed from one value to another. Setting it
+// for the first time or removing it does not wipe the node.
+func wipeTriggerChanged(prior, planned types.String) bool {
+	if prior.IsNull() || prior.IsUnknown() || planned.IsNull() || planned.IsUnknown() {
+		return false
+	}
+	return prior.ValueString() != planned.ValueString()
+}
+
+// hasNodeConfigSource tells whether the startup configuration of the node is managed by
+// Terraform, through config or config_file.
+func hasNodeConfigSource(m nodeResourceModel) bool {
+	return !m.Config.IsNull() || !m.ConfigFile.IsNull()
+}
+
+// nodeConfigEnabled returns the config setting of EVE-NG telling whether the node boots with
+// its startup configuration.
+func nodeConfigEnabled(enabled types.Bool) json.Number {
+	if enabled.ValueBool() {
+		return "1"
+	}
+	return "0"
+}
+
+// stringOrTemplate returns the planned value of an attribute, or the default value of the
+// template option when the attribute is not set.
+func stringOrTemplate(value types.String, tmpl *nodeTemplate, key string) string {
+	if !value.IsUnknown() && !value.IsNull() {
+		return value.ValueString()
+	}
+	return tmpl.Value(key)
+}
+
+func (r *nodeResource) NewNodeModel(ctx context.Context, labPath string, nodeId int) (nodeResourceModel, error) {
+	node, err := r.client.Node.GetNodeDetails(ctx, labPath, nodeId)
 	if err != nil {
 		return nodeResourceModel{}, err
 	}
@@ -423,37 +1273,73 @@ func (r *nodeResource) NewNodeModel(labPath string, nodeId int) (nodeResourceMod
 	model.Cpu = types.Int64Value(int64(node.Cpu))
 	model.Ethernet = types.Int64Value(int64(node.Ethernet))
 	model.Uuid = types.StringValue(node.Uuid)
+	model.QemuOptions = types.StringValue(string(node.QemuOptions))
+	model.QemuVersion = types.StringValue(string(node.QemuVersion))
+	model.QemuArch = types.StringValue(string(node.QemuArch))
+	model.QemuNic = types.StringValue(string(node.QemuNic))
+	model.Cpulimit = types.BoolValue(node.Cpulimit == "1")
+	model.Firstmac = types.StringValue(string(node.Firstmac))
+	model.Serial = apiInt64Value(node.Serial)
+	model.Nvram = apiInt64Value(node.Nvram)
+	model.Idlepc = apiStringValue(node.Idlepc)
+	// Empty slots are left out, EVE-NG reporting every slot of Dynamips nodes.
+	modules := make(map[string]string)
+	for slot, module := range node.Slots {
+		if module != "" {
+			modules[slot] = module
+		}
+	}
+	slots, diags := types.MapValueFrom(ctx, types.StringType, modules)
+	if diags.HasError() {
+		return nodeResourceModel{}, fmt.Errorf("failed to read slots: %v", diags)
+	}
+	model.Slots = slots
+	model.DockerTag = apiStringValue(node.DockerTag)
+	model.DockerArgs = apiStringValue(node.DockerArgs)
+	model.DockerIp = apiStringValue(node.DockerIp)
+	model.DockerGateway = apiStringValue(node.DockerGateway)
+	env, diags := types.MapValueFrom(ctx, types.StringType, parseDockerEnv(string(node.DockerEnv)))
+	if diags.HasError() {
+		return nodeResourceModel{}, fmt.Errorf("failed to read docker_env: %v", diags)
+	}
+	model.DockerEnv = env
 	model.Id = types.Int64Value(int64(node.Id))
-	config, err := r.client.Node.GetNodeConfig(labPath, nodeId)
+	model.State = types.StringValue(nodeStateStopped)
+	if isNodeRunning(node.Status) {
+		model.State = types.StringValue(nodeStateStarted)
+	}
+	config, err := r.client.Node.GetNodeConfig(ctx, labPath, nodeId)
 	if err != nil {
 		return nodeResourceModel{}, err
 	}
+	model.Config = newNodeConfigNull()
 	if config != "" {
-		model.Config = types.StringValue(config)
+		model.Config = newNodeConfigValue(config)
 	}
+	model.ConfigVars = types.MapNull(types.StringType)
+	model.ConfigRendered = newNodeConfigValue(config)
+	model.ConfigSha256 = types.StringValue(nodeConfigSha256(config))
+	model.ConfigEnabled = types.BoolValue(node.Config.String() == "1")
 	model.LabPath = types.StringValue(labPath)
 	return model, nil
 }
 
-func (r *nodeResource) NewInterfaceModel(labPath string, nodeId int) (interfacesResourceModel, error) {
-	interfaces, err := r.client.Node.GetNodeInterfaces(labPath, nodeId)
+func (r *nodeResource) NewInterfaceModel(ctx context.Context, labPath string, nodeId int) (interfacesResourceModel, error) {
+	interfaces, err := r.client.Node.GetNodeInterfaces(ctx, labPath, nodeId)
 	if err != nil {
 		return interfacesResourceModel{}, err
 	}
+	return newInterfacesModel(interfaces)
+}
+
+// newInterfacesModel returns the names of the interfaces, ordered by index.
+func newInterfacesModel(interfaces *evengsdk.Interfaces) (interfacesResourceModel, error) {
 	model := interfacesResourceModel{}
-	var serialInts []attr.Value
-	for _, s := range interfaces.Serial {
-		serialInts = append(serialInts, types.StringValue(s.Name))
-	}
-	serial, diags := types.ListValue(types.StringType, serialInts)
+	serial, diags := types.ListValue(types.StringType, interfaceNames(interfaces.Serial))
 	if diags.HasError() {
 		return model, errors.New("Failed to create serial interfaces list")
 	}
-	var ethernetInts []attr.Value
-	for _, e := range interfaces.Ethernet {
-		ethernetInts = append(ethernetInts, types.StringValue(e.Name))
-	}
-	ethernet, diags := types.ListValue(types.StringType, ethernetInts)
+	ethernet, diags := types.ListValue(types.StringType, interfaceNames(interfaces.Ethernet))
 	if diags.HasError() {
 		return model, errors.New("Failed to create ethernet interfaces list")
 	}
@@ -462,6 +1348,16 @@ func (r *nodeResource) NewInterfaceModel(labPath string, nodeId int) (interfaces
 	return model, nil
 }
 
+// interfaceNames returns the names of the interfaces ordered by index, the API returning them
+// in a map.
+func interfaceNames(interfaces evengsdk.InterfaceEntry) []attr.Value {
+	names := []attr.Value{}
+	for _, index := range slices.Sorted(maps.Keys(interfaces)) {
+		names = append(names, types.StringValue(interfaces[index].Name))
+	}
+	return names
+}
+
 func (m interfacesResourceModel) AttributeTypes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"serial":   types.ListType{ElemType: types.StringType},
@@ -5,9 +5,14 @@ package provider
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccNodeResource(t *testing.T) {
@@ -21,34 +26,157 @@ func TestAccNodeResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node.test", "lab_path", "/terraform-acceptance-test-node.unl"),
 					resource.TestCheckResourceAttr("eveng_node.test", "name", "acceptance-test"),
-					resource.TestCheckResourceAttr("eveng_node.test", "icon", "PC-2D-Desktop-Generic-S.svg"),
-					resource.TestCheckResourceAttr("eveng_node.test", "ram", "1024"),
-					resource.TestCheckResourceAttr("eveng_node.test", "cpu", "1"),
-					resource.TestCheckResourceAttr("eveng_node.test", "ethernet", "4"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "icon"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "ram"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "cpu"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "ethernet"),
 					resource.TestCheckResourceAttr("eveng_node.test", "top", "0"),
 					resource.TestCheckResourceAttr("eveng_node.test", "left", "0"),
+					resource.TestCheckResourceAttr("eveng_node.test", "state", "stopped"),
+					resource.TestCheckResourceAttr("eveng_node.test", "config_enabled", "true"),
+					// SHA-256 of the empty configuration.
+					resource.TestCheckResourceAttr("eveng_node.test", "config_sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
 				),
 			},
+			// ImportState testing
+			{
+				ResourceName:      "eveng_node.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccNodeImportStateIdFunc("eveng_node.test"),
+			},
 			// Update and Read testing
 			{
 				Config: testAccNodeResourceConfig("acceptance-test-update"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("eveng_node.test", "lab_path", "/terraform-acceptance-test-node.unl"),
 					resource.TestCheckResourceAttr("eveng_node.test", "name", "acceptance-test-update"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "icon"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "ram"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "cpu"),
+					resource.TestCheckResourceAttrSet("eveng_node.test", "ethernet"),
+					resource.TestCheckResourceAttr("eveng_node.test", "top", "0"),
+					resource.TestCheckResourceAttr("eveng_node.test", "left", "0"),
+					resource.TestCheckResourceAttr("eveng_node.test", "state", "stopped"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccNodeResourceVpcs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccNodeResourceTypeConfig("acceptance-test-vpcs", "vpcs"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_node.test", "lab_path", "/terraform-acceptance-test-node.unl"),
+					resource.TestCheckResourceAttr("eveng_node.test", "name", "acceptance-test-vpcs"),
+					resource.TestCheckResourceAttr("eveng_node.test", "type", "vpcs"),
 					resource.TestCheckResourceAttr("eveng_node.test", "icon", "PC-2D-Desktop-Generic-S.svg"),
 					resource.TestCheckResourceAttr("eveng_node.test", "ram", "1024"),
 					resource.TestCheckResourceAttr("eveng_node.test", "cpu", "1"),
 					resource.TestCheckResourceAttr("eveng_node.test", "ethernet", "4"),
-					resource.TestCheckResourceAttr("eveng_node.test", "top", "0"),
-					resource.TestCheckResourceAttr("eveng_node.test", "left", "0"),
+					resource.TestCheckResourceAttr("eveng_node.test", "state", "stopped"),
 				),
 			},
+			// ImportState testing
+			{
+				ResourceName:      "eveng_node.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccNodeImportStateIdFunc("eveng_node.test"),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
 
-func testAccNodeResourceConfig(configurableAttribute string) string {
+func TestAccNodeResourceConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "vpcs.tmpl")
+	err := os.WriteFile(configFile, []byte("set pcname {{ .Name }}\r\nip {{ .Vars.ip }}\r\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNodeResourceConfigFileConfig(configFile, "10.0.0.1/24"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("eveng_node.test", "config"),
+					resource.TestMatchResourceAttr("eveng_node.test", "config_rendered", regexp.MustCompile(`set pcname acceptance-test\s+ip 10.0.0.1/24`)),
+					resource.TestCheckResourceAttr("eveng_node.test", "config_enabled", "true"),
+				),
+			},
+			{
+				Config: testAccNodeResourceConfigFileConfig(configFile, "10.0.0.2/24"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("eveng_node.test", "config_rendered", regexp.MustCompile(`set pcname acceptance-test\s+ip 10.0.0.2/24`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeResourceConfigFileConfig(configFile string, ip string) string {
+	return fmt.Sprintf(`
+resource "eveng_lab" "test" {
+	name = "terraform-acceptance-test-node"
+	author = "terraform-acctest"
+	body = "terraform acceptance test"
+	description = "terraform acceptance test"
+}
+
+resource "eveng_node" "test" {
+  lab_path = eveng_lab.test.path
+  name = "acceptance-test"
+  template = "vpcs"
+  type = "vpcs"
+  config_file = %[1]q
+  config_vars = {
+    ip = %[2]q
+  }
+}
+`, configFile, ip)
+}
+
+func TestAccNodeResourceImportConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNodeResourceImportConfig("acceptance-test", "config = \"set pcname imported\""),
+			},
+			// The imported state replaces the one of the created node, as if the node had been
+			// created outside of Terraform.
+			{
+				ResourceName:       "eveng_node.test",
+				ImportState:        true,
+				ImportStatePersist: true,
+				ImportStateIdFunc:  testAccNodeImportStateIdFunc("eveng_node.test"),
+			},
+			// Updating the node without config leaves the imported configuration as is.
+			{
+				Config: testAccNodeResourceImportConfig("acceptance-test-update", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("eveng_node.test", "name", "acceptance-test-update"),
+					resource.TestCheckNoResourceAttr("eveng_node.test", "config"),
+					resource.TestMatchResourceAttr("eveng_node.test", "config_rendered", regexp.MustCompile(`set pcname imported`)),
+					resource.TestCheckResourceAttr("eveng_node.test", "config_enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNodeResourceImportConfig(name string, config string) string {
 	return fmt.Sprintf(`
 resource "eveng_lab" "test" {
 	name = "terraform-acceptance-test-node"
@@ -61,7 +189,183 @@ resource "eveng_node" "test" {
   lab_path = eveng_lab.test.path
   name = %[1]q
   template = "vpcs"
-  type = "qemu"
+  type = "vpcs"
+  %[2]s
+}
+`, name, config)
+}
+
+func TestAccNodeResourceInvalidTemplate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccNodeResourceTemplateConfig("terraform-acceptance-test-missing", "qemu"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Unknown node template"),
+			},
+			{
+				Config:      testAccNodeResourceTemplateConfig("vpcs", "qemu"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Node type does not match the template"),
+			},
+		},
+	})
 }
-`, configurableAttribute)
+
+func TestAccNodeResourceTypeAttributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "eveng_node" "test" {
+  lab_path = "/terraform-acceptance-test-node.unl"
+  name = "acceptance-test"
+  template = "vpcs"
+  type = "vpcs"
+  serial = 1
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Attribute not supported by the node type"),
+			},
+			{
+				Config: `
+resource "eveng_node" "test" {
+  lab_path = "/terraform-acceptance-test-node.unl"
+  name = "acceptance-test"
+  template = "vpcs"
+  type = "vpcs"
+  docker_env = {
+    FOO = "bar"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Attribute not supported by the node type"),
+			},
+			{
+				Config: `
+resource "eveng_node" "test" {
+  lab_path = "/terraform-acceptance-test-node.unl"
+  name = "acceptance-test"
+  template = "c7200"
+  type = "dynamips"
+  slots = {
+    slot1 = "PA-4T"
+  }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid slot number"),
+			},
+		},
+	})
+}
+
+func testAccNodeResourceTemplateConfig(template string, nodeType string) string {
+	return fmt.Sprintf(`
+resource "eveng_node" "test" {
+  lab_path = "/terraform-acceptance-test-node.unl"
+  name = "acceptance-test"
+  template = %[1]q
+  type = %[2]q
+}
+`, template, nodeType)
+}
+
+func testAccNodeImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", resourceName)
+		}
+		return rs.Primary.Attributes["lab_path"] + ":" + rs.Primary.Attributes["id"], nil
+	}
+}
+
+func testAccNodeResourceConfig(configurableAttribute string) string {
+	return testAccNodeResourceTypeConfig(configurableAttribute, "qemu")
+}
+
+// testAccNodeTemplates are the templates the acceptance tests create the nodes of each type from.
+var testAccNodeTemplates = map[string]string{
+	"qemu": "viosl2",
+	"vpcs": "vpcs",
+}
+
+func testAccNodeResourceTypeConfig(configurableAttribute string, nodeType string) string {
+	return fmt.Sprintf(`
+resource "eveng_lab" "test" {
+	name = "terraform-acceptance-test-node"
+	author = "terraform-acctest"
+	body = "terraform acceptance test"
+	description = "terraform acceptance test"
+}
+
+resource "eveng_node" "test" {
+  lab_path = eveng_lab.test.path
+  name = %[1]q
+  template = %[3]q
+  type = %[2]q
+}
+`, configurableAttribute, nodeType, testAccNodeTemplates[nodeType])
+}
+
+func TestParseNodeImportId(t *testing.T) {
+	for _, test := range []struct {
+		id      string
+		labPath string
+		nodeId  int
+		err     bool
+	}{
+		{id: "/lab.unl:1", labPath: "/lab.unl", nodeId: 1},
+		{id: "/labs/core.unl:12", labPath: "/labs/core.unl", nodeId: 12},
+		{id: "/labs/a:b/core.unl:3", labPath: "/labs/a:b/core.unl", nodeId: 3},
+		{id: "/labs/core.unl", err: true},
+		{id: "/labs/core.unl:", err: true},
+		{id: ":1", err: true},
+		{id: "/labs/core:1", err: true},
+		{id: "/labs/core.unl:node", err: true},
+		{id: "/labs/core.unl:0", err: true},
+		{id: "/labs/core.unl:-1", err: true},
+	} {
+		labPath, nodeId, err := parseNodeImportId(test.id)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.id, err)
+			continue
+		}
+		if labPath != test.labPath || nodeId != test.nodeId {
+			t.Errorf("%q: expected %s and %d, got %s and %d", test.id, test.labPath, test.nodeId, labPath, nodeId)
+		}
+	}
+}
+
+func TestWipeTriggerChanged(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		prior   types.String
+		planned types.String
+		changed bool
+	}{
+		{name: "unset", prior: types.StringNull(), planned: types.StringNull()},
+		{name: "first assignment", prior: types.StringNull(), planned: types.StringValue("1")},
+		{name: "removed", prior: types.StringValue("1"), planned: types.StringNull()},
+		{name: "unknown", prior: types.StringValue("1"), planned: types.StringUnknown()},
+		{name: "unchanged", prior: types.StringValue("1"), planned: types.StringValue("1")},
+		{name: "changed", prior: types.StringValue("1"), planned: types.StringValue("2"), changed: true},
+	} {
+		if changed := wipeTriggerChanged(test.prior, test.planned); changed != test.changed {
+			t.Errorf("%s: expected %t, got %t", test.name, test.changed, changed)
+		}
+	}
 }
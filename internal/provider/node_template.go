@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// templateMissingSuffix is appended by EVE-NG to the description of the templates without any
+// image installed.
+const templateMissingSuffix = ".missing"
+
+// nodeTemplate is a node template of EVE-NG and the options a node created from it defaults to.
+type nodeTemplate struct {
+	Name        string
+	Description string
+	Type        string
+	Options     map[string]templateOption
+}
+
+// templateOption is an option of a node template. List holds the allowed values of the
+// options displayed as a list by EVE-NG, such as image or console.
+type templateOption struct {
+	Value string
+	List  []string
+}
+
+// getNodeTemplate returns the template with the given name.
+func getNodeTemplate(ctx context.Context, client *Client, name string) (*nodeTemplate, error) {
+	raw, err := client.Node.GetTemplate(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	return newNodeTemplate(name, raw), nil
+}
+
+// newNodeTemplate converts a template returned by the EVE-NG API.
+func newNodeTemplate(name string, raw map[string]interface{}) *nodeTemplate {
+	tmpl := &nodeTemplate{
+		Name:        name,
+		Description: templateString(raw["description"]),
+		Type:        templateString(raw["type"]),
+		Options:     make(map[string]templateOption),
+	}
+	options, _ := raw["options"].(map[string]interface{})
+	for key, value := range options {
+		option, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tmpl.Options[key] = templateOption{
+			Value: templateString(option["value"]),
+			List:  templateList(option["list"]),
+		}
+	}
+	if tmpl.Type == "" {
+		tmpl.Type = tmpl.Options["type"].Value
+	}
+	return tmpl
+}
+
+// Value returns the default value of an option, or "" when the template does not have it.
+func (t *nodeTemplate) Value(key string) string {
+	return t.Options[key].Value
+}
+
+// Int64Value returns the default value of a numeric option, or nil when the template does not
+// have it or it is not a number.
+func (t *nodeTemplate) Int64Value(key string) *int64 {
+	value, err := strconv.ParseInt(t.Value(key), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// Images returns the images installed for the template, sorted by name.
+func (t *nodeTemplate) Images() []string {
+	return t.Options["image"].List
+}
+
+// Types returns the node types the template allows: the values of its type option when EVE-NG
+// lists several, or the type of the template otherwise.
+func (t *nodeTemplate) Types() []string {
+	if types := t.Options["type"].List; len(types) > 0 {
+		return types
+	}
+	if t.Type == "" {
+		return nil
+	}
+	return []string{t.Type}
+}
+
+// templateString converts a value of the API to a string, numbers being decoded as float64.
+func templateString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// templateList returns the sorted keys of a list option, which the API returns either as an
+// object or as an array.
+func templateList(value interface{}) []string {
+	var list []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			list = append(list, key)
+		}
+	case []interface{}:
+		for _, item := range v {
+			list = append(list, templateString(item))
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// splitTemplateDescription returns the description of a template listed by the API, and
+// whether images are installed for it.
+func splitTemplateDescription(description string) (string, bool) {
+	if strings.HasSuffix(description, templateMissingSuffix) {
+		return strings.TrimSuffix(description, templateMissingSuffix), false
+	}
+	return description, true
+}
@@ -0,0 +1,245 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	waitForStatusRunning = "status_running"
+	waitForConsolePrompt = "console_prompt"
+
+	nodeWaitInterval     = 5 * time.Second
+	consoleDialTimeout   = 10 * time.Second
+	consoleReadTimeout   = 5 * time.Second
+	consoleMaxBufferSize = 64 * 1024
+)
+
+// nodeWaiter blocks until the nodes of a lab satisfy a readiness condition.
+type nodeWaiter struct {
+	client    *Client
+	condition string
+	prompt    *regexp.Regexp
+}
+
+// newNodeWaiter returns a waiter for the given condition. The prompt is only used by the
+// "console_prompt" condition and must then be a valid regular expression.
+func newNodeWaiter(client *Client, condition string, prompt string) (*nodeWaiter, error) {
+	waiter := &nodeWaiter{client: client, condition: condition}
+	if condition == waitForConsolePrompt {
+		re, err := regexp.Compile(prompt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid console prompt %q: %w", prompt, err)
+		}
+		waiter.prompt = re
+	}
+	return waiter, nil
+}
+
+// Wait polls the lab until every node in nodeIds is ready, or until ctx is done.
+// When nodeIds is empty every node of the lab is waited for.
+func (w *nodeWaiter) Wait(ctx context.Context, labPath string, nodeIds []int) error {
+	nodes, err := getNodesById(ctx, w.client, labPath)
+	if err != nil {
+		return err
+	}
+	pending := make(map[int]string)
+	if len(nodeIds) == 0 {
+		for id, node := range nodes {
+			pending[id] = node.Name
+		}
+	}
+	for _, id := range nodeIds {
+		node, ok := nodes[id]
+		if !ok {
+			return fmt.Errorf("node %d not found in lab %s", id, labPath)
+		}
+		pending[id] = node.Name
+	}
+
+	for {
+		for id, name := range pending {
+			node, ok := nodes[id]
+			if !ok {
+				continue
+			}
+			ready, reason := w.isReady(ctx, node)
+			if ready {
+				tflog.Info(ctx, "Node is ready", map[string]interface{}{
+					"lab_path":  labPath,
+					"node_id":   id,
+					"node_name": name,
+					"condition": w.condition,
+				})
+				delete(pending, id)
+				continue
+			}
+			tflog.Debug(ctx, "Waiting for node", map[string]interface{}{
+				"lab_path":  labPath,
+				"node_id":   id,
+				"node_name": name,
+				"condition": w.condition,
+				"reason":    reason,
+			})
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		tflog.Info(ctx, fmt.Sprintf("Waiting for %d node(s) to be ready", len(pending)), map[string]interface{}{
+			"lab_path":  labPath,
+			"condition": w.condition,
+		})
+
+		select {
+		case <-ctx.Done():
+			var names []string
+			for id, name := range pending {
+				names = append(names, fmt.Sprintf("%s (%d)", name, id))
+			}
+			sort.Strings(names)
+			return fmt.Errorf("nodes %s did not satisfy %q before the timeout: %w", strings.Join(names, ", "), w.condition, ctx.Err())
+		case <-time.After(nodeWaitInterval):
+		}
+
+		nodes, err = getNodesById(ctx, w.client, labPath)
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Failed to read nodes: %s", err), map[string]interface{}{
+				"lab_path": labPath,
+			})
+		}
+	}
+}
+
+// isReady reports whether the node satisfies the condition, along with the reason when it does not.
+func (w *nodeWaiter) isReady(ctx context.Context, node evengsdk.Node) (bool, string) {
+	if !isNodeRunning(node.Status) {
+		return false, fmt.Sprintf("node status is %d", node.Status)
+	}
+	if w.condition != waitForConsolePrompt {
+		return true, ""
+	}
+	matched, err := consoleMatches(ctx, node.Url, w.prompt)
+	if err != nil {
+		return false, err.Error()
+	}
+	if !matched {
+		return false, "console prompt not found"
+	}
+	return true, ""
+}
+
+// getNodesById returns the nodes of the lab keyed by node id.
+func getNodesById(ctx context.Context, client *Client, labPath string) (map[int]evengsdk.Node, error) {
+	nodes, err := client.Node.GetNodes(ctx, labPath)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[int]evengsdk.Node, len(nodes))
+	for key, node := range nodes {
+		if node.Id == 0 {
+			node.Id, err = strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected node id %q", key)
+			}
+		}
+		byId[node.Id] = node
+	}
+	return byId, nil
+}
+
+// consoleMatches connects to the telnet console of a node, sends a new line and reports
+// whether the output matches the prompt.
+func consoleMatches(ctx context.Context, consoleUrl string, prompt *regexp.Regexp) (bool, error) {
+	u, err := url.Parse(consoleUrl)
+	if err != nil {
+		return false, err
+	}
+	if u.Scheme != "telnet" {
+		return false, fmt.Errorf("console %q is not a telnet console", consoleUrl)
+	}
+	dialer := net.Dialer{Timeout: consoleDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(consoleReadTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return false, err
+	}
+	_, err = conn.Write([]byte("\r\n"))
+	if err != nil {
+		return false, err
+	}
+
+	var output []byte
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		output = append(output, stripTelnetCommands(buf[:n])...)
+		if len(output) > consoleMaxBufferSize {
+			output = output[len(output)-consoleMaxBufferSize:]
+		}
+		if prompt.Match(output) {
+			return true, nil
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+}
+
+// stripTelnetCommands removes the telnet negotiation sequences from the console output.
+func stripTelnetCommands(data []byte) []byte {
+	const (
+		iac  = 255
+		sb   = 250
+		se   = 240
+		will = 251
+	)
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != iac {
+			out = append(out, data[i])
+			continue
+		}
+		if i+1 >= len(data) {
+			break
+		}
+		switch cmd := data[i+1]; {
+		case cmd == iac:
+			out = append(out, iac)
+			i++
+		case cmd == sb:
+			for i += 2; i+1 < len(data) && !(data[i] == iac && data[i+1] == se); i++ {
+			}
+			i++
+		case cmd >= will:
+			i += 2
+		default:
+			i++
+		}
+	}
+	return out
+}
@@ -5,9 +5,16 @@ package provider
 
 import (
 	"context"
-	"github.com/CorentinPtrl/evengsdk"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
@@ -31,9 +38,23 @@ type EvengProvider struct {
 
 // EvengProviderModel describes the provider data model.
 type EvengProviderModel struct {
-	Host     types.String `tfsdk:"host"`
-	Username types.String `tfsdk:"username"`
-	Password types.String `tfsdk:"password"`
+	Host       types.String `tfsdk:"host"`
+	Username   types.String `tfsdk:"username"`
+	Password   types.String `tfsdk:"password"`
+	Insecure   types.Bool   `tfsdk:"insecure"`
+	CaCertPem  types.String `tfsdk:"ca_cert_pem"`
+	CaCertFile types.String `tfsdk:"ca_cert_file"`
+	ClientCert types.String `tfsdk:"client_cert"`
+	ClientKey  types.String `tfsdk:"client_key"`
+
+	MaxRetries           types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin         types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax         types.String `tfsdk:"retry_wait_max"`
+	RetryableStatusCodes types.List   `tfsdk:"retryable_status_codes"`
+
+	LabWriteConcurrency types.Int64 `tfsdk:"lab_write_concurrency"`
+
+	SessionCacheFile types.String `tfsdk:"session_cache_file"`
 }
 
 func (p *EvengProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -58,6 +79,67 @@ func (p *EvengProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Sensitive:   true,
 				Description: "The password for the Eveng API. (Can also be set with the EVE_PASSWORD environment variable)",
 			},
+			"insecure": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Skip the verification of the TLS certificate of the Eveng API, for servers using a self-signed certificate. Defaults to true when no CA certificate is set, false otherwise. (Can also be set with the EVE_INSECURE environment variable)",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded CA certificate used to verify the TLS certificate of the Eveng API. (Can also be set with the EVE_CA_CERT_PEM environment variable)",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM encoded CA certificate used to verify the TLS certificate of the Eveng API. (Can also be set with the EVE_CA_CERT_FILE environment variable)",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate, or the path to it, presented to an Eveng API requiring mutual TLS. (Can also be set with the EVE_CLIENT_CERT environment variable)",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded private key of client_cert, or the path to it. (Can also be set with the EVE_CLIENT_KEY environment variable)",
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of times a call to the Eveng API failing with a transient error is retried. Calls creating objects are only retried when the connection to the server failed. Defaults to 3. (Can also be set with the EVE_MAX_RETRIES environment variable)",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: "Minimum wait before retrying a call, doubled after each attempt, as a duration such as \"1s\". Defaults to \"1s\". (Can also be set with the EVE_RETRY_WAIT_MIN environment variable)",
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum wait before retrying a call, as a duration such as \"30s\". Defaults to \"30s\". (Can also be set with the EVE_RETRY_WAIT_MAX environment variable)",
+			},
+			"lab_write_concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of resources allowed to modify the same lab at once. Operations on different labs always run in parallel. Defaults to 1. (Can also be set with the EVE_LAB_WRITE_CONCURRENCY environment variable)",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"retryable_status_codes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Description: "HTTP status codes of the responses considered transient, in addition to connection errors. Defaults to [429, 502, 503, 504]. (Can also be set with the EVE_RETRYABLE_STATUS_CODES environment variable, as a comma-separated list)",
+			},
+			"session_cache_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file the session cookie of the Eveng API is kept in across Terraform runs, so that each run does not log in again. The cached session is checked with the API before being reused. The file holds credentials and is created readable by its owner only. Disabled by default. (Can also be set with the EVE_SESSION_CACHE_FILE environment variable)",
+			},
 		},
 	}
 }
@@ -101,6 +183,40 @@ func (p *EvengProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	if config.MaxRetries.IsUnknown() || config.RetryWaitMin.IsUnknown() || config.RetryWaitMax.IsUnknown() || config.RetryableStatusCodes.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Eveng API Retry Configuration",
+			"The provider cannot create the Eveng API client as there is an unknown configuration value for max_retries, retry_wait_min, retry_wait_max or retryable_status_codes. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if config.LabWriteConcurrency.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("lab_write_concurrency"),
+			"Unknown Eveng Lab Write Concurrency",
+			"The provider cannot create the Eveng API client as there is an unknown configuration value for lab_write_concurrency. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the EVE_LAB_WRITE_CONCURRENCY environment variable.",
+		)
+	}
+
+	if config.SessionCacheFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("session_cache_file"),
+			"Unknown Eveng Session Cache File",
+			"The provider cannot create the Eveng API client as there is an unknown configuration value for session_cache_file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the EVE_SESSION_CACHE_FILE environment variable.",
+		)
+	}
+
+	if config.Insecure.IsUnknown() || config.CaCertPem.IsUnknown() || config.CaCertFile.IsUnknown() || config.ClientCert.IsUnknown() || config.ClientKey.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Eveng API TLS Configuration",
+			"The provider cannot create the Eveng API client as there is an unknown configuration value for insecure, ca_cert_pem, ca_cert_file, client_cert or client_key. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the EVE_INSECURE, EVE_CA_CERT_PEM, EVE_CA_CERT_FILE, EVE_CLIENT_CERT or EVE_CLIENT_KEY environment variables.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -111,6 +227,26 @@ func (p *EvengProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	host := os.Getenv("EVE_HOST")
 	username := os.Getenv("EVE_USER")
 	password := os.Getenv("EVE_PASSWORD")
+	caCertPem := os.Getenv("EVE_CA_CERT_PEM")
+	caCertFile := os.Getenv("EVE_CA_CERT_FILE")
+	clientCert := os.Getenv("EVE_CLIENT_CERT")
+	clientKey := os.Getenv("EVE_CLIENT_KEY")
+	sessionCacheFile := os.Getenv("EVE_SESSION_CACHE_FILE")
+	insecure := false
+	insecureSet := false
+	if value := os.Getenv("EVE_INSECURE"); value != "" {
+		var err error
+		insecure, err = strconv.ParseBool(value)
+		insecureSet = true
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure"),
+				"Invalid EVE_INSECURE Environment Variable",
+				fmt.Sprintf("The EVE_INSECURE environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+	}
 
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
@@ -124,6 +260,57 @@ func (p *EvengProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		password = config.Password.ValueString()
 	}
 
+	labWriteConcurrency := 1
+	if value := os.Getenv("EVE_LAB_WRITE_CONCURRENCY"); value != "" {
+		var err error
+		labWriteConcurrency, err = strconv.Atoi(value)
+		if err != nil || labWriteConcurrency < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("lab_write_concurrency"),
+				"Invalid EVE_LAB_WRITE_CONCURRENCY Environment Variable",
+				fmt.Sprintf("The EVE_LAB_WRITE_CONCURRENCY environment variable must be an integer greater than 0, got %q.", value),
+			)
+			return
+		}
+	}
+
+	if !config.LabWriteConcurrency.IsNull() {
+		labWriteConcurrency = int(config.LabWriteConcurrency.ValueInt64())
+	}
+
+	if !config.SessionCacheFile.IsNull() {
+		sessionCacheFile = config.SessionCacheFile.ValueString()
+	}
+
+	if !config.Insecure.IsNull() {
+		insecure = config.Insecure.ValueBool()
+		insecureSet = true
+	}
+
+	if !config.CaCertPem.IsNull() || !config.CaCertFile.IsNull() {
+		caCertPem = config.CaCertPem.ValueString()
+		caCertFile = config.CaCertFile.ValueString()
+	}
+
+	if !config.ClientCert.IsNull() {
+		clientCert = config.ClientCert.ValueString()
+		clientKey = config.ClientKey.ValueString()
+	}
+
+	// Earlier versions never verified the certificate of the server, keep doing so unless the
+	// configuration asks for the verification.
+	if !insecureSet && caCertPem == "" && caCertFile == "" {
+		insecure = true
+		if strings.HasPrefix(strings.ToLower(host), "https://") {
+			resp.Diagnostics.AddWarning(
+				"Eveng API TLS Certificate Not Verified",
+				"The TLS certificate of the Eveng API is not verified as neither insecure nor a CA certificate is set. "+
+					"Set insecure = false (or EVE_INSECURE=false) to verify it against the system CA certificates, "+
+					"or set ca_cert_pem or ca_cert_file to verify it against an internal CA.",
+			)
+		}
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -161,7 +348,26 @@ func (p *EvengProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	client, err := evengsdk.NewBasicAuthClient(username, password, "0", host)
+	retry, diags := newRetryPolicy(ctx, config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tlsConfig, err := newTLSConfig(insecure, caCertPem, caCertFile, clientCert, clientKey)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Eveng API TLS Configuration",
+			"The provider cannot load the certificates used to connect to the Eveng API: "+err.Error(),
+		)
+		return
+	}
+
+	client, err := NewClient(ctx, username, password, host, tlsConfig, retry, labWriteConcurrency, sessionCacheFile)
+	if detail := tlsErrorDetail(host, err); err != nil && detail != "" {
+		resp.Diagnostics.AddError("Unable to connect to the Eveng API", detail)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Eveng API client",
@@ -177,6 +383,99 @@ func (p *EvengProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	resp.ResourceData = client
 }
 
+// newRetryPolicy returns the retry policy from the configuration, the EVE_* environment
+// variables and the defaults, in this order of precedence.
+func newRetryPolicy(ctx context.Context, config EvengProviderModel) (RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	retry := DefaultRetryPolicy()
+
+	if value := os.Getenv("EVE_MAX_RETRIES"); value != "" {
+		maxRetries, err := strconv.Atoi(value)
+		if err != nil || maxRetries < 0 {
+			diags.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid EVE_MAX_RETRIES Environment Variable",
+				fmt.Sprintf("The EVE_MAX_RETRIES environment variable must be a positive integer, got %q.", value),
+			)
+		} else {
+			retry.MaxRetries = maxRetries
+		}
+	}
+	if !config.MaxRetries.IsNull() {
+		retry.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	for _, wait := range []struct {
+		name   string
+		env    string
+		value  types.String
+		target *time.Duration
+	}{
+		{"retry_wait_min", "EVE_RETRY_WAIT_MIN", config.RetryWaitMin, &retry.WaitMin},
+		{"retry_wait_max", "EVE_RETRY_WAIT_MAX", config.RetryWaitMax, &retry.WaitMax},
+	} {
+		value := os.Getenv(wait.env)
+		if !wait.value.IsNull() {
+			value = wait.value.ValueString()
+		}
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			diags.AddAttributeError(
+				path.Root(wait.name),
+				"Invalid Eveng API Retry Wait",
+				fmt.Sprintf("The %s value must be a positive duration such as \"5s\", got %q. It can be set in the configuration or with the %s environment variable.", wait.name, value, wait.env),
+			)
+			continue
+		}
+		*wait.target = duration
+	}
+	if retry.WaitMin > retry.WaitMax {
+		diags.AddAttributeError(
+			path.Root("retry_wait_min"),
+			"Invalid Eveng API Retry Wait",
+			fmt.Sprintf("retry_wait_min (%s) must not be greater than retry_wait_max (%s).", retry.WaitMin, retry.WaitMax),
+		)
+	}
+
+	if value := os.Getenv("EVE_RETRYABLE_STATUS_CODES"); value != "" {
+		statuses, err := parseStatusCodes(value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("retryable_status_codes"),
+				"Invalid EVE_RETRYABLE_STATUS_CODES Environment Variable",
+				fmt.Sprintf("The EVE_RETRYABLE_STATUS_CODES environment variable must be a comma-separated list of HTTP status codes, got %q: %s.", value, err),
+			)
+		} else {
+			retry.RetryableStatuses = statuses
+		}
+	}
+	if !config.RetryableStatusCodes.IsNull() {
+		var statuses []int64
+		diags.Append(config.RetryableStatusCodes.ElementsAs(ctx, &statuses, false)...)
+		retry.RetryableStatuses = make([]int, 0, len(statuses))
+		for _, status := range statuses {
+			retry.RetryableStatuses = append(retry.RetryableStatuses, int(status))
+		}
+	}
+	return retry, diags
+}
+
+// parseStatusCodes parses a comma-separated list of HTTP status codes, such as "429, 503".
+func parseStatusCodes(value string) ([]int, error) {
+	var statuses []int
+	for _, field := range strings.Split(value, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid status code %q", strings.TrimSpace(field))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
 func (p *EvengProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewFolderResource,
@@ -185,6 +484,7 @@ func (p *EvengProvider) Resources(ctx context.Context) []func() resource.Resourc
 		NewNetworkResource,
 		NewNodeLinkResource,
 		NewStartNodesResource,
+		NewLabStateResource,
 	}
 }
 
@@ -192,6 +492,10 @@ func (p *EvengProvider) DataSources(ctx context.Context) []func() datasource.Dat
 	return []func() datasource.DataSource{
 		NewFolderDataSource,
 		NewTopologyDataSource,
+		NewTemplatesDataSource,
+		NewTemplateDataSource,
+		NewImagesDataSource,
+		NewNodeConfigDataSource,
 	}
 }
 
@@ -4,6 +4,7 @@
 package provider
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -21,3 +22,15 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 func testAccPreCheck(t *testing.T) {
 
 }
+
+func TestParseStatusCodes(t *testing.T) {
+	statuses, err := parseStatusCodes("429, 503,504")
+	if err != nil || !slices.Equal(statuses, []int{429, 503, 504}) {
+		t.Errorf("expected [429 503 504], got %v and %v", statuses, err)
+	}
+	for _, value := range []string{"429,", "abc", "42", "429;503"} {
+		if _, err := parseStatusCodes(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// sessionCacheFile stores the session cookies of EVE-NG across provider runs, so that each
+// plan or apply does not log in again. Sessions are keyed by user and host, several provider
+// configurations being allowed to share the file. The file holds credentials and is only
+// readable by its owner.
+type sessionCacheFile struct {
+	path string
+}
+
+// cachedSession is a session stored in the cache file.
+type cachedSession struct {
+	Cookie string `json:"cookie"`
+	Pro    bool   `json:"pro"`
+}
+
+func sessionCacheKey(username, host string) string {
+	return username + "@" + host
+}
+
+// load returns the session of the user on the host, or nil when the file does not hold one.
+func (f *sessionCacheFile) load(username, host string) (*session, error) {
+	sessions, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	cached, ok := sessions[sessionCacheKey(username, host)]
+	if !ok || cached.Cookie == "" {
+		return nil, nil
+	}
+	return &session{
+		cookie: &http.Cookie{Name: sessionCookie, Value: cached.Cookie},
+		pro:    cached.Pro,
+	}, nil
+}
+
+// save stores the session of the user on the host, keeping the sessions of the others.
+func (f *sessionCacheFile) save(username, host string, s *session) error {
+	sessions, err := f.read()
+	if err != nil {
+		// Overwrite a corrupted file rather than failing every run.
+		sessions = make(map[string]cachedSession)
+	}
+	sessions[sessionCacheKey(username, host)] = cachedSession{Cookie: s.cookie.Value, Pro: s.pro}
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	// Write a temporary file renamed over the cache, so that concurrent runs never read a
+	// partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *sessionCacheFile) read() (map[string]cachedSession, error) {
+	sessions := make(map[string]cachedSession)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionCacheFile(t *testing.T) {
+	cache := &sessionCacheFile{path: filepath.Join(t.TempDir(), "eveng", "sessions.json")}
+
+	s, err := cache.load("admin", "https://eve-ng/")
+	if err != nil || s != nil {
+		t.Fatalf("expected no session in a missing file, got %v and %v", s, err)
+	}
+
+	err = cache.save("admin", "https://eve-ng/", &session{cookie: &http.Cookie{Name: sessionCookie, Value: "admin-cookie"}, pro: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cache.save("user", "https://eve-ng/", &session{cookie: &http.Cookie{Name: sessionCookie, Value: "user-cookie"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(cache.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the file to be readable by its owner only, got %s", info.Mode().Perm())
+	}
+
+	s, err = cache.load("admin", "https://eve-ng/")
+	if err != nil || s == nil {
+		t.Fatalf("expected the session of admin, got %v and %v", s, err)
+	}
+	if s.cookie.Name != sessionCookie || s.cookie.Value != "admin-cookie" || !s.pro {
+		t.Errorf("unexpected session of admin: %s=%s, pro %t", s.cookie.Name, s.cookie.Value, s.pro)
+	}
+	s, err = cache.load("user", "https://eve-ng/")
+	if err != nil || s == nil || s.cookie.Value != "user-cookie" || s.pro {
+		t.Fatalf("expected the session of user to be kept, got %v and %v", s, err)
+	}
+	s, err = cache.load("admin", "https://other/")
+	if err != nil || s != nil {
+		t.Fatalf("expected no session on another host, got %v and %v", s, err)
+	}
+}
+
+func TestSessionCacheFileCorrupted(t *testing.T) {
+	cache := &sessionCacheFile{path: filepath.Join(t.TempDir(), "sessions.json")}
+	if err := os.WriteFile(cache.path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.load("admin", "https://eve-ng/"); err == nil {
+		t.Fatal("expected an error reading a corrupted file")
+	}
+	err := cache.save("admin", "https://eve-ng/", &session{cookie: &http.Cookie{Name: sessionCookie, Value: "cookie"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := cache.load("admin", "https://eve-ng/")
+	if err != nil || s == nil || s.cookie.Value != "cookie" {
+		t.Fatalf("expected the corrupted file to be replaced, got %v and %v", s, err)
+	}
+}
@@ -7,17 +7,31 @@ import (
 	"context"
 	"fmt"
 	"github.com/CorentinPtrl/evengsdk"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 )
 
+const defaultStartNodesTimeout = 20 * time.Minute
+
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &startNodesResource{}
-	_ resource.ResourceWithConfigure = &startNodesResource{}
+	_ resource.Resource                   = &startNodesResource{}
+	_ resource.ResourceWithConfigure      = &startNodesResource{}
+	_ resource.ResourceWithValidateConfig = &startNodesResource{}
 )
 
 // NewStartNodesResource is a helper function to simplify the provider implementation.
@@ -27,13 +41,25 @@ func NewStartNodesResource() resource.Resource {
 
 // startNodesResource is the resource implementation.
 type startNodesResource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 // startNodesResourceModel describes the resource data model.
 type startNodesResourceModel struct {
-	LabPath   basetypes.StringValue `tfsdk:"lab_path"`
-	StartTime basetypes.Int64Value  `tfsdk:"start_time"`
+	LabPath       basetypes.StringValue `tfsdk:"lab_path"`
+	StartTime     basetypes.Int64Value  `tfsdk:"start_time"`
+	WaitFor       basetypes.StringValue `tfsdk:"wait_for"`
+	ConsolePrompt basetypes.StringValue `tfsdk:"console_prompt"`
+	Timeouts      timeouts.Value        `tfsdk:"timeouts"`
+	BootGroups    []bootGroupModel      `tfsdk:"boot_groups"`
+}
+
+// bootGroupModel describes a group of nodes started together.
+type bootGroupModel struct {
+	NodeIds       []basetypes.Int64Value `tfsdk:"node_ids"`
+	Delay         basetypes.Int64Value   `tfsdk:"delay"`
+	WaitFor       basetypes.StringValue  `tfsdk:"wait_for"`
+	ConsolePrompt basetypes.StringValue  `tfsdk:"console_prompt"`
 }
 
 // Metadata returns the resource type name.
@@ -49,11 +75,11 @@ func (r *startNodesResource) Configure(_ context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -63,21 +89,109 @@ func (r *startNodesResource) Configure(_ context.Context, req resource.Configure
 }
 
 // Schema defines the schema for the resource.
-func (r *startNodesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *startNodesResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"lab_path": schema.StringAttribute{
 				Required:    true,
 				Description: "Path of the lab.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"start_time": schema.Int64Attribute{
 				Computed:    true,
 				Description: "Time when the nodes were started.",
 			},
+			"wait_for": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(waitForStatusRunning, waitForConsolePrompt),
+				},
+				Description: "Condition every node must meet before the apply completes, either \"status_running\" or \"console_prompt\". By default the nodes are not waited for.",
+			},
+			"console_prompt": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("wait_for")),
+				},
+				Description: "Regular expression matched against the telnet console of each node when wait_for is \"console_prompt\".",
+			},
+			"boot_groups": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Ordered groups of nodes to start one after the other. Nodes that are not part of any group are started last. When unset, every node is started at once.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node_ids": schema.ListAttribute{
+							Required:    true,
+							ElementType: types.Int64Type,
+							Description: "IDs of the nodes of the group.",
+						},
+						"delay": schema.Int64Attribute{
+							Optional: true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+							Description: "Seconds to wait after the group is started before starting the next one, counted from the start of the group so that the wait for wait_for is included. Defaults to the largest delay of the nodes of the group.",
+						},
+						"wait_for": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(waitForStatusRunning, waitForConsolePrompt),
+							},
+							Description: "Condition every node of the group must meet before the next group is started, either \"status_running\" or \"console_prompt\".",
+						},
+						"console_prompt": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("wait_for")),
+							},
+							Description: "Regular expression matched against the telnet console of each node of the group when wait_for is \"console_prompt\".",
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				CreateDescription: "Time to wait for the nodes to be ready on creation (e.g. \"30m\"). Defaults to \"20m\".",
+				UpdateDescription: "Time to wait for the nodes to be ready on update (e.g. \"30m\"). Defaults to \"20m\".",
+			}),
 		},
 	}
 }
 
+// ValidateConfig validates the wait conditions.
+func (r *startNodesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config startNodesResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.WaitFor.ValueString() == waitForConsolePrompt && config.ConsolePrompt.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("console_prompt"), "Missing console prompt",
+			"console_prompt must be set when wait_for is \"console_prompt\".")
+	}
+	if !config.ConsolePrompt.IsNull() && !config.ConsolePrompt.IsUnknown() {
+		if _, err := regexp.Compile(config.ConsolePrompt.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("console_prompt"), "Invalid console prompt", err.Error())
+		}
+	}
+	for i, group := range config.BootGroups {
+		if group.WaitFor.ValueString() == waitForConsolePrompt && group.ConsolePrompt.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("boot_groups").AtListIndex(i).AtName("console_prompt"), "Missing console prompt",
+				"console_prompt must be set when wait_for is \"console_prompt\".")
+		}
+		if !group.ConsolePrompt.IsNull() && !group.ConsolePrompt.IsUnknown() {
+			if _, err := regexp.Compile(group.ConsolePrompt.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("boot_groups").AtListIndex(i).AtName("console_prompt"), "Invalid console prompt", err.Error())
+			}
+		}
+	}
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *startNodesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan startNodesResourceModel
@@ -86,16 +200,38 @@ func (r *startNodesResource) Create(ctx context.Context, req resource.CreateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.Lab.GetLab(plan.LabPath.ValueString())
+	timeout, diags := plan.Timeouts.Create(ctx, defaultStartNodesTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	_, err = r.client.Lab.GetLab(ctx, plan.LabPath.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read lab", err.Error())
 		return
 	}
-	startTime, err := r.StartLab(plan)
+	startTime, err := r.StartLab(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to start nodes", err.Error())
 		return
 	}
+	// Waiting for the nodes does not modify the lab.
+	unlock()
+	err = r.WaitForNodes(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Nodes are not ready", err.Error())
+		return
+	}
 	plan.StartTime = basetypes.NewInt64Value(startTime)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -123,16 +259,38 @@ func (r *startNodesResource) Update(ctx context.Context, req resource.UpdateRequ
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	_, err := r.client.Lab.GetLab(plan.LabPath.ValueString())
+	timeout, diags := plan.Timeouts.Update(ctx, defaultStartNodesTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock, err := r.client.LockLab(ctx, plan.LabPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to lock lab", err.Error())
+		return
+	}
+	defer unlock()
+
+	_, err = r.client.Lab.GetLab(ctx, plan.LabPath.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read lab", err.Error())
 		return
 	}
-	startTime, err := r.StartLab(plan)
+	startTime, err := r.StartLab(ctx, plan)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to start nodes", err.Error())
 		return
 	}
+	// Waiting for the nodes does not modify the lab.
+	unlock()
+	err = r.WaitForNodes(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Nodes are not ready", err.Error())
+		return
+	}
 	plan.StartTime = basetypes.NewInt64Value(startTime)
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -146,32 +304,265 @@ func (r *startNodesResource) Delete(ctx context.Context, req resource.DeleteRequ
 
 }
 
-func (r *startNodesResource) StartLab(model startNodesResourceModel) (int64, error) {
-	currentLab, err := r.client.GetAuth()
+// WaitForNodes blocks until every node of the lab meets the wait_for condition, if any.
+func (r *startNodesResource) WaitForNodes(ctx context.Context, model startNodesResourceModel) error {
+	if model.WaitFor.IsNull() {
+		return nil
+	}
+	waiter, err := newNodeWaiter(r.client, model.WaitFor.ValueString(), model.ConsolePrompt.ValueString())
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, "Waiting for nodes to be ready", map[string]interface{}{
+		"lab_path": model.LabPath.ValueString(),
+		"wait_for": model.WaitFor.ValueString(),
+	})
+	return waiter.Wait(ctx, model.LabPath.ValueString(), nil)
+}
+
+func (r *startNodesResource) StartLab(ctx context.Context, model startNodesResourceModel) (int64, error) {
+	if len(model.BootGroups) == 0 {
+		return startLab(ctx, r.client, model.LabPath.ValueString())
+	}
+	err := releaseLab(ctx, r.client, model.LabPath.ValueString())
 	if err != nil {
 		return 0, err
 	}
-	if currentLab.Lab != model.LabPath.ValueString() && currentLab.Lab != "" {
-		err = r.client.Node.StopNodes(currentLab.Lab)
+	startTime := time.Now().Unix()
+	return startTime, r.BootGroups(ctx, model)
+}
+
+// BootGroups starts the boot groups in order, waiting for each group to be ready and for
+// its delay to elapse since the group was started before starting the next one. Remaining nodes are started last.
+func (r *startNodesResource) BootGroups(ctx context.Context, model startNodesResourceModel) error {
+	labPath := model.LabPath.ValueString()
+	nodes, err := getNodesById(ctx, r.client, labPath)
+	if err != nil {
+		return fmt.Errorf("Failed to read nodes: %w", err)
+	}
+	started := make(map[int]bool, len(nodes))
+	for i, group := range model.BootGroups {
+		var ids []int
+		for _, id := range group.NodeIds {
+			nodeId := int(id.ValueInt64())
+			if _, ok := nodes[nodeId]; !ok {
+				return fmt.Errorf("node %d of boot group %d not found in lab %s", nodeId, i, labPath)
+			}
+			ids = append(ids, nodeId)
+		}
+		tflog.Info(ctx, "Starting boot group", map[string]interface{}{
+			"lab_path": labPath,
+			"group":    i,
+			"node_ids": ids,
+		})
+		groupStart := time.Now()
+		err = r.startNodes(ctx, labPath, ids, nodes, started)
+		if err != nil {
+			return err
+		}
+		if !group.WaitFor.IsNull() {
+			waiter, err := newNodeWaiter(r.client, group.WaitFor.ValueString(), group.ConsolePrompt.ValueString())
+			if err != nil {
+				return err
+			}
+			err = waiter.Wait(ctx, labPath, ids)
+			if err != nil {
+				return fmt.Errorf("boot group %d: %w", i, err)
+			}
+		}
+		if len(started) == len(nodes) {
+			return nil
+		}
+		// The delay counts from the start of the group, the wait for readiness included.
+		err = sleepContext(ctx, bootGroupDelay(group, ids, nodes)-time.Since(groupStart))
 		if err != nil {
-			return 0, fmt.Errorf("Failed to stop nodes: %w", err)
+			return fmt.Errorf("boot group %d: %w", i, err)
 		}
 	}
-	tries := 5
-	delay := 15
-	if currentLab.Lab == model.LabPath.ValueString() || currentLab.Lab == "" {
-		tries = 0
+
+	var remaining []int
+	for id := range nodes {
+		if !started[id] {
+			remaining = append(remaining, id)
+		}
 	}
-	for i := 0; i < tries; i++ {
-		err = r.client.Lab.CloseLab()
-		time.Sleep(time.Duration(delay) * time.Second)
+	sort.Ints(remaining)
+	tflog.Info(ctx, "Starting nodes outside of boot groups", map[string]interface{}{
+		"lab_path": labPath,
+		"node_ids": remaining,
+	})
+	return r.startNodes(ctx, labPath, remaining, nodes, started)
+}
+
+// startNodes starts the nodes that are neither started by a previous group nor already running.
+func (r *startNodesResource) startNodes(ctx context.Context, labPath string, ids []int, nodes map[int]evengsdk.Node, started map[int]bool) error {
+	for _, id := range ids {
+		if started[id] {
+			continue
+		}
+		if isNodeRunning(nodes[id].Status) {
+			started[id] = true
+			continue
+		}
+		err := r.client.Node.StartNode(ctx, labPath, id)
+		if err != nil {
+			return fmt.Errorf("Failed to start node %d: %w", id, checkLabHeld(ctx, r.client, labPath, err))
+		}
+		started[id] = true
+	}
+	return nil
+}
+
+// bootGroupDelay returns the delay of the group, falling back to the largest delay of its nodes.
+// EVE-NG expresses node delays in seconds.
+func bootGroupDelay(group bootGroupModel, ids []int, nodes map[int]evengsdk.Node) time.Duration {
+	if !group.Delay.IsNull() && !group.Delay.IsUnknown() {
+		return time.Duration(group.Delay.ValueInt64()) * time.Second
 	}
+	delay := 0
+	for _, id := range ids {
+		if nodes[id].Delay > delay {
+			delay = nodes[id].Delay
+		}
+	}
+	return time.Duration(delay) * time.Second
+}
+
+// sleepContext waits for the duration to elapse, or returns early when ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// labSwitchPhase is a step of the routine that releases the lab opened by the session
+// before the nodes of another lab are started.
+type labSwitchPhase int
+
+const (
+	phaseCheckSession labSwitchPhase = iota
+	phaseStopNodes
+	phaseCloseLab
+	phaseWaitRelease
+	phaseReleased
+)
+
+const (
+	labReleaseWaitMin = 1 * time.Second
+	labReleaseWaitMax = 15 * time.Second
+)
+
+// startLab releases the lab currently opened by the session, if it differs from labPath,
+// and starts every node of labPath. It returns the time at which the nodes were started.
+func startLab(ctx context.Context, client *Client, labPath string) (int64, error) {
+	err := releaseLab(ctx, client, labPath)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to close lab: %w", err)
+		return 0, err
 	}
-	err = r.client.Node.StartNodes(model.LabPath.ValueString())
+	err = client.Node.StartNodes(ctx, labPath)
 	if err != nil {
-		return 0, fmt.Errorf("Failed to start nodes: %w", err)
+		return 0, fmt.Errorf("Failed to start nodes: %w", checkLabHeld(ctx, client, labPath, err))
 	}
 	return time.Now().Unix(), nil
 }
+
+// checkLabHeld explains err, a failure to start nodes of labPath, when the lab is held by a
+// session other than the one of the provider: api/auth does not report the lab as opened by
+// the session, yet the lab is locked. The session cannot release such a lab, so closing labs
+// does not help. err is returned as is otherwise.
+func checkLabHeld(ctx context.Context, client *Client, labPath string, err error) error {
+	auth, authErr := client.GetAuth(ctx)
+	if authErr != nil || auth.Lab == labPath {
+		return err
+	}
+	locked, lockErr := client.Lab.IsLocked(ctx, labPath)
+	if lockErr != nil || !locked {
+		return err
+	}
+	return fmt.Errorf("lab %s is locked but not opened by the session of user %q, so another session holds it, "+
+		"such as another user working on it in the EVE-NG web interface; close the lab there and retry: %w", labPath, auth.Username, err)
+}
+
+// releaseLab releases the lab currently opened by the session, if it differs from labPath.
+//
+// EVE-NG binds a single opened lab to each user, so the previous lab has its nodes stopped and
+// is closed, then the session is polled with an exponential backoff until it no longer reports
+// a lab. The routine gives up when ctx is done.
+func releaseLab(ctx context.Context, client *Client, labPath string) error {
+	phase := phaseCheckSession
+	wait := labReleaseWaitMin
+	var currentLab string
+	var closeErr error
+	for {
+		switch phase {
+		case phaseCheckSession:
+			auth, err := client.GetAuth(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed to read session: %w", err)
+			}
+			currentLab = auth.Lab
+			phase = phaseReleased
+			if currentLab != "" && currentLab != labPath {
+				tflog.Info(ctx, "Another lab is opened by the session", map[string]interface{}{
+					"lab_path":    labPath,
+					"current_lab": currentLab,
+					"username":    auth.Username,
+				})
+				phase = phaseStopNodes
+			}
+		case phaseStopNodes:
+			err := client.Node.StopNodes(ctx, currentLab)
+			if err != nil {
+				return fmt.Errorf("Failed to stop nodes of %s: %w", currentLab, err)
+			}
+			phase = phaseCloseLab
+		case phaseCloseLab:
+			closeErr = client.Lab.CloseLab(ctx)
+			if closeErr != nil {
+				tflog.Debug(ctx, fmt.Sprintf("Failed to close lab: %s", closeErr), map[string]interface{}{
+					"current_lab": currentLab,
+				})
+			}
+			phase = phaseWaitRelease
+		case phaseWaitRelease:
+			auth, err := client.GetAuth(ctx)
+			if err == nil && (auth.Lab == "" || auth.Lab == labPath) {
+				tflog.Info(ctx, "Lab released", map[string]interface{}{
+					"current_lab": currentLab,
+				})
+				phase = phaseReleased
+				continue
+			}
+			tflog.Debug(ctx, "Waiting for the lab to be released", map[string]interface{}{
+				"current_lab": currentLab,
+				"wait":        wait.String(),
+			})
+			select {
+			case <-ctx.Done():
+				msg := fmt.Sprintf("Lab %s is still opened by the session", currentLab)
+				if auth != nil {
+					msg = fmt.Sprintf("Lab %s is still opened by the session of user %q", currentLab, auth.Username)
+				}
+				msg += ". It may be held by another session of the same user, such as the EVE-NG web interface; close the lab there and retry"
+				if closeErr != nil {
+					msg += fmt.Sprintf(" (last close error: %s)", closeErr)
+				}
+				return fmt.Errorf("%s: %w", msg, ctx.Err())
+			case <-time.After(wait):
+			}
+			wait *= 2
+			if wait > labReleaseWaitMax {
+				wait = labReleaseWaitMax
+			}
+			phase = phaseCloseLab
+		case phaseReleased:
+			return nil
+		}
+	}
+}
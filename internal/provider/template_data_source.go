@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &templateDataSource{}
+	_ datasource.DataSourceWithConfigure = &templateDataSource{}
+)
+
+func NewTemplateDataSource() datasource.DataSource {
+	return &templateDataSource{}
+}
+
+type templateDataSource struct {
+	client *Client
+}
+
+type TemplateDataSourceModel struct {
+	Name        string            `tfsdk:"name"`
+	Description string            `tfsdk:"description"`
+	Type        string            `tfsdk:"type"`
+	Icon        types.String      `tfsdk:"icon"`
+	Cpu         types.Int64       `tfsdk:"cpu"`
+	Ram         types.Int64       `tfsdk:"ram"`
+	Ethernet    types.Int64       `tfsdk:"ethernet"`
+	Console     types.String      `tfsdk:"console"`
+	Consoles    []string          `tfsdk:"consoles"`
+	QemuOptions types.String      `tfsdk:"qemu_options"`
+	QemuVersion types.String      `tfsdk:"qemu_version"`
+	QemuArch    types.String      `tfsdk:"qemu_arch"`
+	QemuNic     types.String      `tfsdk:"qemu_nic"`
+	Image       types.String      `tfsdk:"image"`
+	Images      []string          `tfsdk:"images"`
+	Options     map[string]string `tfsdk:"options"`
+}
+
+func (d *templateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template"
+}
+
+func (d *templateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *templateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a node template and the options nodes created from it default to.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the template (e.g. vios).",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Description of the template.",
+			},
+			"type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of the nodes created from the template (e.g. qemu, iol, dynamips).",
+			},
+			"icon": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default icon.",
+			},
+			"cpu": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Default number of CPUs.",
+			},
+			"ram": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Default amount of RAM in MB.",
+			},
+			"ethernet": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Default number of ethernet interfaces.",
+			},
+			"console": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default console type.",
+			},
+			"consoles": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Console types supported by the template.",
+			},
+			"qemu_options": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default QEMU command line options.",
+			},
+			"qemu_version": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default QEMU version.",
+			},
+			"qemu_arch": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default QEMU architecture.",
+			},
+			"qemu_nic": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default QEMU network interface model.",
+			},
+			"image": schema.StringAttribute{
+				Computed:    true,
+				Description: "Default image.",
+			},
+			"images": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Images installed for the template, sorted by name.",
+			},
+			"options": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Default value of every option of the template, keyed by option name.",
+			},
+		},
+	}
+}
+
+func (d *templateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TemplateDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tmpl, err := getNodeTemplate(ctx, d.client, state.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read template", err.Error())
+		return
+	}
+
+	state.Description = tmpl.Description
+	state.Type = tmpl.Type
+	state.Icon = templateStringValue(tmpl, "icon")
+	state.Cpu = templateInt64Value(tmpl, "cpu")
+	state.Ram = templateInt64Value(tmpl, "ram")
+	state.Ethernet = templateInt64Value(tmpl, "ethernet")
+	state.Console = templateStringValue(tmpl, "console")
+	state.Consoles = append([]string{}, tmpl.Options["console"].List...)
+	state.QemuOptions = templateStringValue(tmpl, "qemu_options")
+	state.QemuVersion = templateStringValue(tmpl, "qemu_version")
+	state.QemuArch = templateStringValue(tmpl, "qemu_arch")
+	state.QemuNic = templateStringValue(tmpl, "qemu_nic")
+	state.Image = templateStringValue(tmpl, "image")
+	state.Images = append([]string{}, tmpl.Images()...)
+	state.Options = make(map[string]string, len(tmpl.Options))
+	for key, option := range tmpl.Options {
+		state.Options[key] = option.Value
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// templateStringValue returns the default value of an option, null when the template does not have it.
+func templateStringValue(tmpl *nodeTemplate, key string) types.String {
+	if _, ok := tmpl.Options[key]; !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(tmpl.Value(key))
+}
+
+// templateInt64Value returns the default value of a numeric option, null when the template does not have it.
+func templateInt64Value(tmpl *nodeTemplate, key string) types.Int64 {
+	value := tmpl.Int64Value(key)
+	if value == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(*value)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccEveTemplateDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccTemplateDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.eveng_template.test", "name", "vpcs"),
+					resource.TestCheckResourceAttr("data.eveng_template.test", "type", "vpcs"),
+					resource.TestCheckResourceAttrSet("data.eveng_template.test", "description"),
+					resource.TestCheckResourceAttrSet("data.eveng_template.test", "options.%"),
+				),
+			},
+		},
+	})
+}
+
+const testAccTemplateDataSourceConfig = `
+data "eveng_template" "test" {
+  name = "vpcs"
+}
+`
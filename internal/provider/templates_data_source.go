@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &templatesDataSource{}
+	_ datasource.DataSourceWithConfigure = &templatesDataSource{}
+)
+
+func NewTemplatesDataSource() datasource.DataSource {
+	return &templatesDataSource{}
+}
+
+type templatesDataSource struct {
+	client *Client
+}
+
+type TemplatesDataSourceModel struct {
+	Templates []TemplateSummaryModel `tfsdk:"templates"`
+}
+
+type TemplateSummaryModel struct {
+	Name            string       `tfsdk:"name"`
+	Description     string       `tfsdk:"description"`
+	Type            types.String `tfsdk:"type"`
+	ImagesInstalled bool         `tfsdk:"images_installed"`
+}
+
+func (d *templatesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_templates"
+}
+
+func (d *templatesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *templatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the node templates of the EVE-NG server.",
+		Attributes: map[string]schema.Attribute{
+			"templates": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Node templates, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the template, as used by the template attribute of eveng_node.",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Description of the template.",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Type of the nodes created from the template (e.g. qemu, iol, dynamips). Only known when images are installed for the template.",
+						},
+						"images_installed": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether at least one image is installed for the template.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *templatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state TemplatesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templates, err := d.client.Node.GetTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list templates", err.Error())
+		return
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	state.Templates = []TemplateSummaryModel{}
+	for _, name := range names {
+		description, installed := splitTemplateDescription(templates[name])
+		summary := TemplateSummaryModel{
+			Name:            name,
+			Description:     description,
+			Type:            types.StringNull(),
+			ImagesInstalled: installed,
+		}
+		if installed {
+			tmpl, err := getNodeTemplate(ctx, d.client, name)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to read template", err.Error())
+				return
+			}
+			summary.Type = types.StringValue(tmpl.Type)
+		}
+		state.Templates = append(state.Templates, summary)
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccEveTemplatesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccTemplatesDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.eveng_templates.test", "templates.#"),
+					resource.TestCheckResourceAttrSet("data.eveng_templates.test", "templates.0.name"),
+					resource.TestCheckResourceAttrSet("data.eveng_templates.test", "templates.0.images_installed"),
+				),
+			},
+		},
+	})
+}
+
+const testAccTemplatesDataSourceConfig = `
+data "eveng_templates" "test" {}
+`
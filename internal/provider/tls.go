@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// newTLSConfig returns the TLS configuration of the connections to the EVE-NG server. The CA
+// bundle is added to the system roots; an empty bundle keeps the system roots only. The client
+// certificate and key are either PEM encoded or the path to a PEM file, and are presented to
+// servers requiring mutual TLS.
+func newTLSConfig(insecure bool, caCertPEM string, caCertFile string, clientCert string, clientKey string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure,
+	}
+	if caCertFile != "" {
+		data, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+		}
+		caCertPEM = string(data)
+	}
+	if clientCert != "" || clientKey != "" {
+		certPEM, err := readPEM(clientCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		keyPEM, err := readPEM(clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if caCertPEM == "" {
+		return config, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+		return nil, errors.New("no PEM encoded certificate found in the CA certificate")
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// readPEM returns value when it is PEM encoded, or the content of the file at path value.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// tlsErrorDetail explains a certificate verification failure, or returns "" when err is not one.
+func tlsErrorDetail(host string, err error) string {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	reason := ""
+	switch {
+	case errors.As(err, &unknownAuthority):
+		reason = "The certificate is signed by an unknown authority. If the server uses an internal CA, " +
+			"set ca_cert_pem or ca_cert_file (or the EVE_CA_CERT_PEM or EVE_CA_CERT_FILE environment variable) to its certificate."
+	case errors.As(err, &hostnameErr):
+		reason = "The certificate is not valid for the host name. Make sure host matches one of the names of the certificate."
+	case errors.As(err, &invalidErr):
+		reason = "The certificate is invalid, for instance because it expired or is not yet valid."
+	case errors.As(err, &verifyErr):
+		reason = "The certificate could not be verified."
+	default:
+		return ""
+	}
+	return fmt.Sprintf("The provider could not verify the TLS certificate of %s: %s\n\n%s\n\n"+
+		"If the server uses a self-signed certificate, set insecure = true (or EVE_INSECURE=true) to skip the verification.",
+		host, err, reason)
+}
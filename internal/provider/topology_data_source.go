@@ -6,7 +6,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/CorentinPtrl/evengsdk"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -24,7 +23,7 @@ func NewTopologyDataSource() datasource.DataSource {
 }
 
 type topologyDataSource struct {
-	client *evengsdk.Client
+	client *Client
 }
 
 type TopologyDataSourceModel struct {
@@ -41,11 +40,11 @@ func (d *topologyDataSource) Configure(_ context.Context, req datasource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*evengsdk.Client)
+	client, ok := req.ProviderData.(*Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *evengsdk.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got %T. Report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -77,7 +76,7 @@ func (d *topologyDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	topology, err := d.client.Lab.GetTopology(state.LabPath)
+	topology, err := d.client.Lab.GetTopology(ctx, state.LabPath)
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		return
@@ -126,17 +125,23 @@ func harmonizeMaps(maps []map[string]interface{}) []map[string]interface{} {
 		return maps
 	}
 
+	// The maps come from the client cache and are shared, so harmonized copies are returned.
+	keys := make(map[string]struct{})
+	for _, m := range maps {
+		for k := range m {
+			keys[k] = struct{}{}
+		}
+	}
+	harmonized := make([]map[string]interface{}, len(maps))
 	for i, m := range maps {
-		for d, m2 := range maps {
-			if i == d {
-				continue
-			}
-			for k, _ := range m2 {
-				if _, ok := m[k]; !ok {
-					m[k] = ""
-				}
-			}
+		h := make(map[string]interface{}, len(keys))
+		for k := range keys {
+			h[k] = ""
+		}
+		for k, v := range m {
+			h[k] = v
 		}
+		harmonized[i] = h
 	}
-	return maps
+	return harmonized
 }